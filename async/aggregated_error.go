@@ -3,6 +3,7 @@ package async
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 type (
@@ -11,15 +12,50 @@ type (
 )
 
 func (e AggregatedError) Error() string {
-	return fmt.Sprintf("error aggregated from %d errors", len(e))
+	return e.format("%v")
+}
+
+// Format implements fmt.Formatter.
+// Verbs %v and %s print a line per each error in collection.
+// Verb %+v prints each error in collection with %+v as well (e.g. to include PanicError's stack).
+func (e AggregatedError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprint(s, e.format("%+v"))
+	case verb == 'v' || verb == 's':
+		fmt.Fprint(s, e.Error())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(async.AggregatedError=%s)", verb, e.Error())
+	}
+}
+
+// format error message, printing each error in collection with specified format.
+// Multi-line errors are indented to keep message readable.
+func (e AggregatedError) format(errFormat string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "error aggregated from %d errors", len(e))
+	if len(e) > 0 {
+		b.WriteString(":")
+	}
+
+	for _, err := range e {
+		b.WriteString("\n  * ")
+		b.WriteString(strings.ReplaceAll(fmt.Sprintf(errFormat, err), "\n", "\n    "))
+	}
+
+	return b.String()
 }
 
 // Has reports whether any error in collection matches target.
+// Nested aggregated errors are inspected as well.
 //
 // See errors.Is() documentation for additional information:
 // https://pkg.go.dev/errors#Is
 func (e AggregatedError) Has(target error) bool {
-	for _, err := range e {
+	for _, err := range e.Flatten() {
 		if errors.Is(err, target) {
 			return true
 		}
@@ -29,14 +65,78 @@ func (e AggregatedError) Has(target error) bool {
 
 // Find the first error in collection that matches target, and if one is found, sets target to that error value and returns true.
 // Otherwise, it returns false.
+// Nested aggregated errors are inspected as well.
 //
 // See errors.As() documentation for additional information:
 // https://pkg.go.dev/errors#As
 func (e AggregatedError) Find(target any) bool {
-	for _, err := range e {
+	for _, err := range e.Flatten() {
 		if errors.As(err, target) {
 			return true
 		}
 	}
 	return false
 }
+
+// Count errors in collection that match target.
+// Target is used the same way as in Find(), it is set to the last matched error.
+// Nested aggregated errors are inspected as well.
+func (e AggregatedError) Count(target any) int {
+	count := 0
+	for _, err := range e.Flatten() {
+		if errors.As(err, target) {
+			count++
+		}
+	}
+	return count
+}
+
+// Filter returns collection of errors for which function f returns true.
+// Returns nil if no errors match.
+// Nested aggregated errors are inspected as well.
+func (e AggregatedError) Filter(f func(error) bool) AggregatedError {
+	var res AggregatedError
+	for _, err := range e.Flatten() {
+		if f(err) {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+// Flatten returns collection where nested aggregated errors are replaced with their individual errors.
+func (e AggregatedError) Flatten() AggregatedError {
+	var res AggregatedError
+	for _, err := range e {
+		if nested, ok := err.(AggregatedError); ok {
+			res = append(res, nested.Flatten()...)
+		} else {
+			res = append(res, err)
+		}
+	}
+	return res
+}
+
+// AggregateErrors converts err into a collection of individual errors.
+// Aggregated errors, errors combined by errors.Join() and any other errors implementing "Unwrap() []error" are unwrapped recursively.
+// Returns nil if err is nil.
+func AggregateErrors(err error) AggregatedError {
+	var errs []error
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case AggregatedError:
+		// Unwrap() is only available starting with Go 1.20.
+		errs = e
+	case interface{ Unwrap() []error }:
+		errs = e.Unwrap()
+	default:
+		return AggregatedError{err}
+	}
+
+	var res AggregatedError
+	for _, err := range errs {
+		res = append(res, AggregateErrors(err)...)
+	}
+	return res
+}
@@ -0,0 +1,19 @@
+//go:build go1.20
+
+package async
+
+import (
+	"errors"
+)
+
+// Unwrap returns errors in collection.
+// Allows errors.Is() and errors.As() to inspect individual errors.
+func (e AggregatedError) Unwrap() []error {
+	return e
+}
+
+// Join converts collection into an error produced by errors.Join().
+// Returns nil if collection is empty.
+func (e AggregatedError) Join() error {
+	return errors.Join(e...)
+}
@@ -0,0 +1,72 @@
+//go:build go1.20
+
+package async_test
+
+import (
+	"errors"
+	"testing"
+
+	"dexm.lol/async"
+)
+
+func TestAggregatedError_Unwrap(t *testing.T) {
+	e := async.AggregatedError{
+		dummyError1,
+		dummyError2,
+	}
+
+	unwrapped := e.Unwrap()
+	if len(unwrapped) != 2 || unwrapped[0] != dummyError1 || unwrapped[1] != dummyError2 {
+		t.Errorf("Unexpected errors unwrapped: %#v", unwrapped)
+	}
+}
+
+func TestAggregatedError_standardLibraryInspectsErrors(t *testing.T) {
+	err := error(async.AggregatedError{
+		dummyError1,
+		customErrorType1{"custom error 1"},
+	})
+
+	if !errors.Is(err, dummyError1) {
+		t.Error("errors.Is() did not detect error inside aggregated error")
+	}
+
+	var customError1 customErrorType1
+	if !errors.As(err, &customError1) {
+		t.Error("errors.As() did not detect error inside aggregated error")
+	}
+}
+
+func TestAggregatedError_Join(t *testing.T) {
+	joined := async.AggregatedError{dummyError1, dummyError2}.Join()
+	if !errors.Is(joined, dummyError1) || !errors.Is(joined, dummyError2) {
+		t.Errorf("Unexpected joined error: %#v", joined)
+	}
+
+	if joined := (async.AggregatedError{}).Join(); joined != nil {
+		t.Errorf("Unexpected joined error from empty aggregated error: %#v", joined)
+	}
+
+	// Round trip via errors.Join().
+	e := async.AggregateErrors(errors.Join(joined, dummyError3))
+	if len(e) != 3 || e[0] != dummyError1 || e[1] != dummyError2 || e[2] != dummyError3 {
+		t.Errorf("Unexpected aggregated error from joined error: %#v", e)
+	}
+}
+
+type multiError []error
+
+func (e multiError) Error() string {
+	return "multi error"
+}
+
+func (e multiError) Unwrap() []error {
+	return e
+}
+
+func TestAggregateErrors_multiError(t *testing.T) {
+	e := async.AggregateErrors(multiError{dummyError1, multiError{dummyError2, dummyError3}})
+	if len(e) != 3 || e[0] != dummyError1 || e[1] != dummyError2 || e[2] != dummyError3 {
+		t.Errorf("Unexpected aggregated error from multi error: %#v", e)
+	}
+}
@@ -10,7 +10,8 @@ import (
 
 // Ensure interface implementation
 var (
-	_ error = async.AggregatedError{}
+	_ error         = async.AggregatedError{}
+	_ fmt.Formatter = async.AggregatedError{}
 )
 
 func TestAggregatedError_beingWrapped(t *testing.T) {
@@ -20,7 +21,9 @@ func TestAggregatedError_beingWrapped(t *testing.T) {
 		dummyError3,
 	})
 
-	if err.Error() != "wrapping error: error aggregated from 3 errors" {
+	expectedMessage := "error aggregated from 3 errors:\n  * dummy error 1\n  * dummy error 2\n  * dummy error 3"
+
+	if err.Error() != "wrapping error: "+expectedMessage {
 		t.Errorf("unexpected error message: %s", err.Error())
 	}
 
@@ -29,7 +32,7 @@ func TestAggregatedError_beingWrapped(t *testing.T) {
 		t.Errorf("errors.As() did not detect wrapped async.AggregatedError")
 	}
 
-	if aggregatedError.Error() != "error aggregated from 3 errors" {
+	if aggregatedError.Error() != expectedMessage {
 		t.Errorf("aggregated error was not unwrapped properly: %#v", aggregatedError)
 	}
 }
@@ -80,3 +83,129 @@ func TestAggregatedError_Find(t *testing.T) {
 		t.Error("Did not expected to find error in aggregated error")
 	}
 }
+
+func ExampleAggregatedError() {
+	err := async.AggregatedError{
+		errors.New("1st error"),
+		async.AggregatedError{
+			errors.New("2nd error"),
+			errors.New("3rd error"),
+		},
+	}
+
+	fmt.Println(err)
+
+	// Output:
+	// error aggregated from 2 errors:
+	//   * 1st error
+	//   * error aggregated from 2 errors:
+	//       * 2nd error
+	//       * 3rd error
+}
+
+func TestAggregatedError_Format(t *testing.T) {
+	e := async.AggregatedError{
+		dummyError1,
+		&async.PanicError{Value: "panic happened", Stack: []byte("dummy stack")},
+	}
+
+	expected := "error aggregated from 2 errors:\n  * dummy error 1\n  * asynchronous function panicked: panic happened"
+	if s := fmt.Sprintf("%v", e); s != expected {
+		t.Errorf("Unexpected %%v format: %s", s)
+	}
+
+	expected = "error aggregated from 2 errors:\n  * dummy error 1\n  * asynchronous function panicked: panic happened\n    dummy stack"
+	if s := fmt.Sprintf("%+v", e); s != expected {
+		t.Errorf("Unexpected %%+v format: %s", s)
+	}
+
+	if s := fmt.Sprintf("%v", async.AggregatedError{}); s != "error aggregated from 0 errors" {
+		t.Errorf("Unexpected %%v format of empty aggregated error: %s", s)
+	}
+}
+
+func TestAggregatedError_nested(t *testing.T) {
+	e := async.AggregatedError{
+		dummyError1,
+		fmt.Errorf("wrapping error: %w", dummyError2),
+		async.AggregatedError{
+			customErrorType1{"custom error 1"},
+			async.AggregatedError{
+				dummyError3,
+			},
+		},
+	}
+
+	flattened := e.Flatten()
+	if len(flattened) != 4 || flattened[0] != dummyError1 || flattened[2] != (customErrorType1{"custom error 1"}) || flattened[3] != dummyError3 {
+		t.Errorf("Unexpected flattened errors: %#v", flattened)
+	}
+
+	if !e.Has(dummyError3) {
+		t.Error("Expected to find nested error in aggregated error")
+	}
+
+	var customError1 customErrorType1
+	if !e.Find(&customError1) {
+		t.Error("Expected to find nested error in aggregated error")
+	}
+}
+
+func TestAggregatedError_Count(t *testing.T) {
+	e := async.AggregatedError{
+		customErrorType1{"custom error 1"},
+		customErrorType2{"custom error 2"},
+		async.AggregatedError{
+			fmt.Errorf("wrapping error: %w", customErrorType1{"custom error 3"}),
+		},
+	}
+
+	var customError1 customErrorType1
+	if count := e.Count(&customError1); count != 2 {
+		t.Errorf("Unexpected number of errors counted: %d", count)
+	}
+
+	var customError3 customErrorType3
+	if count := e.Count(&customError3); count != 0 {
+		t.Errorf("Unexpected number of errors counted: %d", count)
+	}
+}
+
+func TestAggregatedError_Filter(t *testing.T) {
+	e := async.AggregatedError{
+		dummyError1,
+		dummyError2,
+		async.AggregatedError{
+			fmt.Errorf("wrapping error: %w", dummyError1),
+		},
+	}
+
+	filtered := e.Filter(func(err error) bool {
+		return errors.Is(err, dummyError1)
+	})
+	if len(filtered) != 2 || filtered[0] != dummyError1 {
+		t.Errorf("Unexpected filtered errors: %#v", filtered)
+	}
+
+	filtered = e.Filter(func(err error) bool {
+		return errors.Is(err, dummyError3)
+	})
+	if filtered != nil {
+		t.Errorf("Unexpected filtered errors: %#v", filtered)
+	}
+}
+
+func TestAggregateErrors(t *testing.T) {
+	if e := async.AggregateErrors(nil); e != nil {
+		t.Errorf("Unexpected aggregated error from nil: %#v", e)
+	}
+
+	if e := async.AggregateErrors(dummyError1); len(e) != 1 || e[0] != dummyError1 {
+		t.Errorf("Unexpected aggregated error from single error: %#v", e)
+	}
+
+	e := async.AggregateErrors(async.AggregatedError{dummyError1, async.AggregatedError{dummyError2, dummyError3}})
+	if len(e) != 3 || e[0] != dummyError1 || e[1] != dummyError2 || e[2] != dummyError3 {
+		t.Errorf("Unexpected aggregated error from nested aggregated error: %#v", e)
+	}
+}
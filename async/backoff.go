@@ -0,0 +1,105 @@
+package async
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes delays between attempts of a repeated operation.
+type Backoff interface {
+	// Delay returns delay before the next attempt.
+	// Retry is the number of the upcoming retry (starting with 1), previous is the delay returned for the previous retry (0 initially).
+	Delay(retry int, previous time.Duration) time.Duration
+}
+
+// ConstantBackoff waits the same amount of time before every attempt.
+type ConstantBackoff time.Duration
+
+// Jitter defines how ExponentialBackoff randomizes delays.
+type Jitter int
+
+// Supported jitter modes.
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ for comparison.
+const (
+	// NoJitter does not randomize delays.
+	NoJitter Jitter = iota
+
+	// FullJitter picks random delay between 0 and exponentially growing delay.
+	FullJitter
+
+	// DecorrelatedJitter picks random delay between initial delay and 3 times previous delay.
+	// Multiplier is not used in this mode.
+	DecorrelatedJitter
+)
+
+// ExponentialBackoff multiplies delay after every attempt.
+type ExponentialBackoff struct {
+	// Initial delay before the first retry.
+	Initial time.Duration
+
+	// Max limits delay, zero means no limit.
+	Max time.Duration
+
+	// Multiplier of the delay after each retry, zero means 2.
+	Multiplier float64
+
+	// Jitter mode, no jitter by default.
+	Jitter Jitter
+
+	// Rand returns random number in [0.0,1.0) for jitter, math/rand is used by default.
+	Rand func() float64
+}
+
+// Delay implements Backoff.
+func (b ConstantBackoff) Delay(int, time.Duration) time.Duration {
+	return time.Duration(b)
+}
+
+// Delay implements Backoff.
+func (b ExponentialBackoff) Delay(retry int, previous time.Duration) time.Duration {
+	random := b.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	var delay float64
+	switch b.Jitter {
+	case DecorrelatedJitter:
+		if previous < b.Initial {
+			previous = b.Initial
+		}
+		delay = float64(b.Initial) + random()*(3*float64(previous)-float64(b.Initial))
+	case FullJitter:
+		delay = random() * b.exponential(retry)
+	default:
+		delay = b.exponential(retry)
+	}
+
+	return time.Duration(b.limit(delay))
+}
+
+// exponential returns delay without jitter.
+func (b ExponentialBackoff) exponential(retry int) float64 {
+	multiplier := b.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	// Protect from overflow on large number of retries.
+	return b.limit(float64(b.Initial) * math.Pow(multiplier, float64(retry-1)))
+}
+
+// limit delay by Max or by the maximum duration if Max is not set.
+// That way delay can always be converted to time.Duration without overflow.
+func (b ExponentialBackoff) limit(delay float64) float64 {
+	if b.Max > 0 && delay > float64(b.Max) {
+		return float64(b.Max)
+	}
+
+	// Float64 representation of the maximum duration is rounded up, so the closest smaller float is used.
+	if maxDelay := math.Nextafter(float64(math.MaxInt64), 0); delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,144 @@
+package async_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ async.Backoff = async.ConstantBackoff(0)
+	_ async.Backoff = async.ExponentialBackoff{}
+)
+
+// delays returns the first n delays produced by backoff b.
+func delays(b async.Backoff, n int) []time.Duration {
+	var res []time.Duration
+	var delay time.Duration
+	for retry := 1; retry <= n; retry++ {
+		delay = b.Delay(retry, delay)
+		res = append(res, delay)
+	}
+	return res
+}
+
+func half() float64 {
+	return 0.5
+}
+
+func TestConstantBackoff(t *testing.T) {
+	actual := delays(async.ConstantBackoff(time.Second), 3)
+	expected := []time.Duration{time.Second, time.Second, time.Second}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected delays: %v", actual)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	actual := delays(async.ExponentialBackoff{
+		Initial: time.Second,
+		Max:     10 * time.Second,
+	}, 6)
+	expected := []time.Duration{
+		time.Second,
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		10 * time.Second,
+		10 * time.Second,
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected delays: %v", actual)
+	}
+}
+
+func TestExponentialBackoff_Multiplier(t *testing.T) {
+	actual := delays(async.ExponentialBackoff{
+		Initial:    time.Second,
+		Multiplier: 3,
+	}, 3)
+	expected := []time.Duration{time.Second, 3 * time.Second, 9 * time.Second}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected delays: %v", actual)
+	}
+}
+
+func TestExponentialBackoff_FullJitter(t *testing.T) {
+	actual := delays(async.ExponentialBackoff{
+		Initial: time.Second,
+		Max:     5 * time.Second,
+		Jitter:  async.FullJitter,
+		Rand:    half,
+	}, 4)
+	expected := []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		2500 * time.Millisecond,
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected delays: %v", actual)
+	}
+}
+
+func TestExponentialBackoff_DecorrelatedJitter(t *testing.T) {
+	actual := delays(async.ExponentialBackoff{
+		Initial: time.Second,
+		Max:     10 * time.Second,
+		Jitter:  async.DecorrelatedJitter,
+		Rand:    half,
+	}, 4)
+	// Each delay is halfway between initial delay and 3 times previous delay.
+	expected := []time.Duration{
+		2 * time.Second,
+		3500 * time.Millisecond,
+		5750 * time.Millisecond,
+		9125 * time.Millisecond,
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected delays: %v", actual)
+	}
+}
+
+func TestExponentialBackoff_doesNotOverflow(t *testing.T) {
+	backoffs := []async.ExponentialBackoff{
+		{Initial: time.Second},
+		{Initial: time.Second, Jitter: async.FullJitter, Rand: half},
+		{Initial: time.Second, Jitter: async.DecorrelatedJitter, Rand: half},
+	}
+
+	for _, backoff := range backoffs {
+		var delay time.Duration
+		for retry := 1; retry <= 100; retry++ {
+			delay = backoff.Delay(retry, delay)
+			if delay <= 0 {
+				t.Fatalf("Delay of retry %d overflowed with jitter %d: %s", retry, backoff.Jitter, delay)
+			}
+		}
+	}
+
+	if delay := (async.ExponentialBackoff{Initial: time.Second}).Delay(1000, 0); delay < 290*365*24*time.Hour {
+		t.Errorf("Delay was not limited by the maximum duration: %s", delay)
+	}
+}
+
+func TestExponentialBackoff_defaultRandomness(t *testing.T) {
+	b := async.ExponentialBackoff{
+		Initial: time.Second,
+		Jitter:  async.FullJitter,
+	}
+
+	for retry := 1; retry <= 10; retry++ {
+		if delay := b.Delay(retry, 0); delay < 0 || delay > time.Second<<(retry-1) {
+			t.Errorf("Delay is out of range for retry %d: %s", retry, delay)
+		}
+	}
+}
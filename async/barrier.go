@@ -0,0 +1,107 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Barrier allows a fixed number of goroutines (parties) to wait for each other.
+// Barrier is cyclic, it is reset and can be used again once all parties have arrived.
+type Barrier struct {
+	parties int
+	action  func()
+
+	mu         sync.Mutex
+	arrived    int
+	generation chan struct{}
+}
+
+// NewBarrier creates barrier for the number of parties.
+// Optional action is called by the last arriving party before other parties are released,
+// e.g. to merge results of the current cycle.
+// Panics with ErrInvalidBarrierParties if parties is less than 1.
+func NewBarrier(parties int, action func()) *Barrier {
+	if parties < 1 {
+		panic(ErrInvalidBarrierParties)
+	}
+
+	return &Barrier{
+		parties:    parties,
+		action:     action,
+		generation: make(chan struct{}),
+	}
+}
+
+// Await arrives at the barrier and blocks until all parties have arrived or context is done.
+// If context is done first, party leaves the barrier and context's error is returned,
+// so the barrier waits for another party to arrive instead.
+func (b *Barrier) Await(ctx context.Context) error {
+	b.mu.Lock()
+
+	generation := b.generation
+	b.arrived++
+
+	if b.arrived == b.parties {
+		// Start a new cycle right away, so the barrier can be reused while action is running.
+		b.arrived = 0
+		b.generation = make(chan struct{})
+		b.mu.Unlock()
+
+		// Make sure waiting parties are released even if action panics.
+		defer close(generation)
+
+		if b.action != nil {
+			b.action()
+		}
+		return nil
+	}
+
+	// Check context first, no need to wait if it is already done.
+	if ctx.Err() != nil {
+		b.arrived--
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-generation:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		tripped := generation != b.generation
+		if !tripped {
+			b.arrived--
+		}
+		b.mu.Unlock()
+
+		// All parties could have arrived while waiting for the lock, wait for barrier action to complete then.
+		if tripped {
+			<-generation
+			return nil
+		}
+		return ctx.Err()
+	}
+}
+
+// Done returns channel, which is closed when all parties arrive at the barrier in the current cycle
+// and barrier action has completed.
+func (b *Barrier) Done() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.generation
+}
+
+// Parties returns the number of parties required to release the barrier.
+func (b *Barrier) Parties() int {
+	return b.parties
+}
+
+// Waiting returns the number of parties currently waiting at the barrier.
+func (b *Barrier) Waiting() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.arrived
+}
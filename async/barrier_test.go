@@ -0,0 +1,136 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleBarrier() {
+	const workers = 3
+
+	var mu sync.Mutex
+	var results []int
+
+	barrier := async.NewBarrier(workers, func() {
+		// Merge results of all workers once all of them have finished the phase.
+		sort.Ints(results)
+		fmt.Println("Phase results:", results)
+		results = nil
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for i := 1; i <= workers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+
+			for phase := 1; phase <= 2; phase++ {
+				mu.Lock()
+				results = append(results, i*phase)
+				mu.Unlock()
+
+				if err := barrier.Await(context.TODO()); err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Output:
+	// Phase results: [1 2 3]
+	// Phase results: [2 4 6]
+}
+
+func TestBarrier_releasesPartiesAfterAction(t *testing.T) {
+	var actions int
+	barrier := async.NewBarrier(2, func() {
+		actions++
+	})
+
+	for cycle := 1; cycle <= 2; cycle++ {
+		done := barrier.Done()
+
+		ch := make(chan error, 1)
+		go func() { ch <- barrier.Await(context.TODO()) }()
+
+		// Give goroutine time to arrive.
+		time.Sleep(time.Millisecond)
+		if waiting := barrier.Waiting(); waiting != 1 {
+			t.Errorf("Unexpected number of waiting parties: %d", waiting)
+		}
+
+		if err := barrier.Await(context.TODO()); err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+		if err := <-ch; err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+
+		select {
+		case <-done:
+		default:
+			t.Error("Done channel was not closed")
+		}
+		if actions != cycle {
+			t.Errorf("Unexpected number of barrier actions: %d", actions)
+		}
+	}
+}
+
+func TestBarrier_AwaitRespectsContext(t *testing.T) {
+	barrier := async.NewBarrier(2, nil)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	if err := barrier.Await(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	// Party, which gave up, has left the barrier.
+	if waiting := barrier.Waiting(); waiting != 0 {
+		t.Errorf("Unexpected number of waiting parties: %d", waiting)
+	}
+}
+
+func TestBarrier_releasesPartiesIfActionPanics(t *testing.T) {
+	barrier := async.NewBarrier(2, func() {
+		panic(dummyError)
+	})
+
+	ch := make(chan error, 1)
+	go func() { ch <- barrier.Await(context.TODO()) }()
+	time.Sleep(time.Millisecond)
+
+	_, err := async.Execute(func() (interface{}, error) {
+		return nil, barrier.Await(context.TODO())
+	})()
+	if !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	if err := <-ch; err != nil {
+		t.Errorf("Unexpected error of waiting party: %#v", err)
+	}
+}
+
+func TestNewBarrier_panicsOnInvalidParties(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidBarrierParties {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewBarrier(0, nil)
+}
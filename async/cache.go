@@ -0,0 +1,185 @@
+package async
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// CacheConfig configures asynchronous loading cache.
+type CacheConfig[K comparable, V any] struct {
+	// Loader loads value for the key, must be set.
+	// Context is not cancelled, since load can be shared by multiple callers.
+	Loader func(context.Context, K) (V, error)
+
+	// TTL after which loaded value expires, zero means values never expire.
+	TTL time.Duration
+
+	// ErrorTTL after which loader's error expires, zero means errors are not cached.
+	ErrorTTL time.Duration
+
+	// RefreshAfter is the age of value after which it is reloaded in background, zero disables refresh-ahead.
+	// Stale value is returned while reloading, should be less than TTL to be useful.
+	// If reload fails, stale value is kept and reload is attempted again on the next access.
+	RefreshAfter time.Duration
+
+	// MaxSize limits the number of cached keys, least recently used keys are evicted first.
+	// Zero means no limit.
+	MaxSize int
+
+	// Clock to measure values' age, SystemClock is used if nil.
+	Clock Clock
+}
+
+// Cache is an asynchronous loading cache.
+// Values are loaded on demand, concurrent requests for the same key share a single load.
+type Cache[K comparable, V any] struct {
+	config CacheConfig[K, V]
+	clock  Clock
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	lru     *list.List
+}
+
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	load       *cacheLoad[V]
+	refreshing bool
+}
+
+type cacheLoad[V any] struct {
+	future   *Future[V]
+	loadedAt time.Time
+}
+
+// NewCache creates asynchronous loading cache.
+// Panics with ErrInvalidCacheConfig if config.Loader is not set or config.MaxSize is negative.
+func NewCache[K comparable, V any](config CacheConfig[K, V]) *Cache[K, V] {
+	if config.Loader == nil || config.MaxSize < 0 {
+		panic(ErrInvalidCacheConfig)
+	}
+
+	return &Cache[K, V]{
+		config:  config,
+		clock:   clockOrDefault(config.Clock),
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get value for the key.
+// Returns future which is either resolved with the cached value, or joins in-flight load, or starts a new one.
+func (c *Cache[K, V]) Get(key K) *Future[V] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		entry := &cacheEntry[K, V]{key: key}
+		elem = c.lru.PushFront(entry)
+		c.entries[key] = elem
+		entry.load = c.load(elem, false)
+
+		c.evict()
+		return entry.load.future
+	}
+
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*cacheEntry[K, V])
+
+	// Join in-flight load.
+	if !entry.load.future.IsDone() {
+		return entry.load.future
+	}
+
+	// Read error directly, since TryGet() raises panic again if panic policy requires so.
+	// Panicked load is treated as failed one.
+	err := entry.load.future.msg.err
+	age := c.clock.Now().Sub(entry.load.loadedAt)
+
+	switch {
+	case err != nil && (c.config.ErrorTTL == 0 || age >= c.config.ErrorTTL):
+		entry.load = c.load(elem, false)
+	case err == nil && c.config.TTL > 0 && age >= c.config.TTL:
+		entry.load = c.load(elem, false)
+	case err == nil && c.config.RefreshAfter > 0 && age >= c.config.RefreshAfter && !entry.refreshing:
+		// Serve stale value while reloading.
+		entry.refreshing = true
+		c.load(elem, true)
+	}
+
+	return entry.load.future
+}
+
+// Invalidate removes the key from cache.
+// Callers which are waiting for in-flight load of this key still receive its result.
+func (c *Cache[K, V]) Invalidate(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// Len returns the number of cached keys, including expired ones which were not accessed since expiration.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+// load value for the entry asynchronously.
+// Refreshing load replaces entry's current load only when it succeeds.
+// Must be called while holding the lock.
+func (c *Cache[K, V]) load(elem *list.Element, refresh bool) *cacheLoad[V] {
+	entry := elem.Value.(*cacheEntry[K, V])
+	load := &cacheLoad[V]{}
+
+	// Future is resolved after load time is recorded, so it is always set for resolved loads.
+	load.future = ExecuteFuture(func() (res V, err error) {
+		// Loader may panic, in which case err is not set.
+		succeeded := false
+
+		defer func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+
+			load.loadedAt = c.clock.Now()
+
+			if refresh {
+				entry.refreshing = false
+
+				// Entry might have been evicted or replaced while reloading.
+				if succeeded && c.entries[entry.key] == elem {
+					entry.load = load
+				}
+			}
+		}()
+
+		res, err = c.config.Loader(context.Background(), entry.key)
+		succeeded = err == nil
+
+		return res, err
+	})
+
+	return load
+}
+
+// evict least recently used entries exceeding maximum size.
+// Must be called while holding the lock.
+func (c *Cache[K, V]) evict() {
+	for c.config.MaxSize > 0 && c.lru.Len() > c.config.MaxSize {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove entry from cache.
+// Must be called while holding the lock.
+func (c *Cache[K, V]) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry[K, V]).key)
+}
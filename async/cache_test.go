@@ -0,0 +1,242 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleCache() {
+	cache := async.NewCache(async.CacheConfig[string, string]{
+		Loader: func(ctx context.Context, key string) (string, error) {
+			// Load value from backend.
+
+			return fmt.Sprintf("value of %s", key), nil
+		},
+		TTL:     time.Minute,
+		MaxSize: 1000,
+	})
+
+	res, err := cache.Get("key").Await()
+	fmt.Println("Result:", res)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Result: value of key
+	// Error: <nil>
+}
+
+// countingLoader returns loader which returns the number of calls for all keys, and its counter.
+func countingLoader(err error) (func(context.Context, string) (int, error), *int32) {
+	var calls int32
+	return func(ctx context.Context, key string) (int, error) {
+		return int(atomic.AddInt32(&calls, 1)), err
+	}, &calls
+}
+
+// awaitValue awaits cache value and checks it.
+func awaitValue(t *testing.T, future *async.Future[int], expected int) {
+	t.Helper()
+
+	res, err := future.Await()
+	if res != expected {
+		t.Errorf("Unexpected value received from the cache: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the cache: %#v", err)
+	}
+}
+
+func TestCache_hit(t *testing.T) {
+	loader, calls := countingLoader(nil)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader})
+
+	awaitValue(t, cache.Get("key"), 1)
+	awaitValue(t, cache.Get("key"), 1)
+
+	if calls := atomic.LoadInt32(calls); calls != 1 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestCache_joinsInFlightLoad(t *testing.T) {
+	chRelease := make(chan struct{})
+
+	var calls int32
+	cache := async.NewCache(async.CacheConfig[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			<-chRelease
+			return int(atomic.AddInt32(&calls, 1)), nil
+		},
+	})
+
+	future1 := cache.Get("key")
+	future2 := cache.Get("key")
+	close(chRelease)
+
+	awaitValue(t, future1, 1)
+	awaitValue(t, future2, 1)
+}
+
+func TestCache_TTL(t *testing.T) {
+	clock := newFakeClock()
+	loader, _ := countingLoader(nil)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader, TTL: time.Minute, Clock: clock})
+
+	awaitValue(t, cache.Get("key"), 1)
+
+	clock.Advance(59 * time.Second)
+	awaitValue(t, cache.Get("key"), 1)
+
+	clock.Advance(time.Second)
+	awaitValue(t, cache.Get("key"), 2)
+}
+
+func TestCache_ErrorTTL(t *testing.T) {
+	clock := newFakeClock()
+	loader, calls := countingLoader(dummyError)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader, TTL: time.Hour, ErrorTTL: time.Minute, Clock: clock})
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get("key").Await(); !errors.Is(err, dummyError) {
+			t.Errorf("Unexpected error received from the cache: %#v", err)
+		}
+	}
+	if calls := atomic.LoadInt32(calls); calls != 1 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+
+	clock.Advance(time.Minute)
+	cache.Get("key").Await()
+
+	if calls := atomic.LoadInt32(calls); calls != 2 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestCache_errorsAreNotCachedByDefault(t *testing.T) {
+	loader, calls := countingLoader(dummyError)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader})
+
+	cache.Get("key").Await()
+	cache.Get("key").Await()
+
+	if calls := atomic.LoadInt32(calls); calls != 2 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestCache_reloadsPanickedLoadWithPanicOnAwait(t *testing.T) {
+	async.SetPanicPolicy(async.PanicPolicy{Mode: async.PanicOnAwait})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	var calls int32
+	cache := async.NewCache(async.CacheConfig[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				panic(dummyError)
+			}
+			return 2, nil
+		},
+	})
+
+	future := cache.Get("key")
+	if panicErr := recoverPanicError(func() { future.Await() }); panicErr == nil {
+		t.Fatal("Panic was not raised again by the future")
+	}
+
+	// Get itself does not panic and treats panicked load as failed one.
+	awaitValue(t, cache.Get("key"), 2)
+}
+
+func TestCache_MaxSize(t *testing.T) {
+	loader, calls := countingLoader(nil)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader, MaxSize: 2})
+
+	awaitValue(t, cache.Get("key 1"), 1)
+	awaitValue(t, cache.Get("key 2"), 2)
+
+	// Make key 1 recently used, so key 2 is evicted.
+	awaitValue(t, cache.Get("key 1"), 1)
+	awaitValue(t, cache.Get("key 3"), 3)
+
+	if size := cache.Len(); size != 2 {
+		t.Errorf("Unexpected cache size: %d", size)
+	}
+
+	awaitValue(t, cache.Get("key 1"), 1)
+	awaitValue(t, cache.Get("key 2"), 4)
+
+	if calls := atomic.LoadInt32(calls); calls != 4 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestCache_RefreshAfter(t *testing.T) {
+	clock := newFakeClock()
+	chRefresh := make(chan struct{})
+
+	var calls int32
+	cache := async.NewCache(async.CacheConfig[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			res := int(atomic.AddInt32(&calls, 1))
+			if res == 2 {
+				<-chRefresh
+			}
+			return res, nil
+		},
+		TTL:          time.Hour,
+		RefreshAfter: time.Minute,
+		Clock:        clock,
+	})
+
+	awaitValue(t, cache.Get("key"), 1)
+
+	// Stale value is served while reloading, only one reload is started.
+	clock.Advance(time.Minute)
+	awaitValue(t, cache.Get("key"), 1)
+	awaitValue(t, cache.Get("key"), 1)
+
+	close(chRefresh)
+
+	// Wait for reload to replace stale value.
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for {
+		res, _ := cache.Get("key").Await()
+		if res == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Stale value was not replaced: %#v", res)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("Loader was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	loader, _ := countingLoader(nil)
+	cache := async.NewCache(async.CacheConfig[string, int]{Loader: loader})
+
+	awaitValue(t, cache.Get("key"), 1)
+	cache.Invalidate("key")
+	awaitValue(t, cache.Get("key"), 2)
+}
+
+func TestNewCache_panicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidCacheConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewCache(async.CacheConfig[string, int]{})
+}
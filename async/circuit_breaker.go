@@ -0,0 +1,312 @@
+package async
+
+import (
+	"sync"
+	"time"
+)
+
+// Number of buckets of the rolling window, which counts failure rate.
+const circuitBreakerBuckets = 10
+
+// CircuitState is a state of circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed state lets all calls through and counts failures.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen state rejects all calls with ErrCircuitOpen until cool-down period elapses.
+	CircuitOpen
+
+	// CircuitHalfOpen state lets limited number of probe calls through.
+	// Circuit closes if all probes succeed and opens again if any of them fails.
+	CircuitHalfOpen
+)
+
+// CircuitBreakerConfig configures circuit breaker.
+// At least one of ConsecutiveFailures and FailureRate thresholds must be set.
+type CircuitBreakerConfig struct {
+	// ConsecutiveFailures opens circuit after this number of consecutive failures, zero disables threshold.
+	ConsecutiveFailures int
+
+	// FailureRate opens circuit when ratio of failed calls within rolling Window reaches it, zero disables threshold.
+	// Must be between 0 and 1.
+	FailureRate float64
+
+	// MinRequests is the minimum number of calls within rolling Window before FailureRate is checked.
+	MinRequests int
+
+	// Window is the duration of rolling window for FailureRate, must be set if FailureRate is set.
+	Window time.Duration
+
+	// CoolDown is the duration of open state before circuit becomes half-open, must be greater than 0.
+	CoolDown time.Duration
+
+	// HalfOpenProbes is the number of probe calls let through in half-open state, 1 by default.
+	HalfOpenProbes int
+
+	// IsFailure reports whether error should be counted as failure, all errors are counted if nil.
+	// Panics are always counted as failures.
+	IsFailure func(error) bool
+
+	// OnStateChange is called when circuit changes its state, e.g. to report metrics.
+	// It is called synchronously, but without holding circuit breaker's lock.
+	OnStateChange func(from, to CircuitState)
+
+	// Clock to measure rolling window and cool-down period, SystemClock is used if nil.
+	Clock Clock
+}
+
+// CircuitBreaker stops calling a failing function for a cool-down period, returning ErrCircuitOpen immediately instead.
+// See Protect() to wrap function with circuit breaker.
+type CircuitBreaker struct {
+	config CircuitBreakerConfig
+	clock  Clock
+
+	mu    sync.Mutex
+	state CircuitState
+
+	// Generation is incremented on every state change,
+	// so results of calls started in the previous state are ignored.
+	generation uint64
+
+	// Closed state counters.
+	consecutiveFailures int
+	buckets             []circuitBucket
+
+	// Open state deadline.
+	openUntil time.Time
+
+	// Half-open state counters.
+	probes          int
+	succeededProbes int
+
+	// State changes to report once the lock is released.
+	changes []circuitStateChange
+}
+
+type circuitBucket struct {
+	start     time.Time
+	successes int
+	failures  int
+}
+
+type circuitStateChange struct {
+	from, to CircuitState
+}
+
+// NewCircuitBreaker creates circuit breaker in closed state.
+// Panics with ErrInvalidCircuitBreakerConfig if config is not valid.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.ConsecutiveFailures < 0 || config.FailureRate < 0 || config.FailureRate > 1 ||
+		config.ConsecutiveFailures == 0 && config.FailureRate == 0 ||
+		config.FailureRate > 0 && config.Window <= 0 ||
+		config.MinRequests < 0 || config.CoolDown <= 0 || config.HalfOpenProbes < 0 {
+		panic(ErrInvalidCircuitBreakerConfig)
+	}
+	if config.HalfOpenProbes == 0 {
+		config.HalfOpenProbes = 1
+	}
+
+	return &CircuitBreaker{
+		config: config,
+		clock:  clockOrDefault(config.Clock),
+	}
+}
+
+// Protect wraps function f with circuit breaker.
+// Returned function calls f if circuit breaker lets the call through and returns ErrCircuitOpen otherwise.
+// Result of every call of f is reported to circuit breaker.
+func Protect[T any](cb *CircuitBreaker, f func() (T, error)) func() (T, error) {
+	return func() (res T, err error) {
+		generation, err := cb.allow()
+		if err != nil {
+			return res, err
+		}
+
+		// Make sure result is reported even if function f panics.
+		completed := false
+		defer func() {
+			cb.report(generation, completed && (err == nil || !cb.isFailure(err)))
+		}()
+
+		res, err = f()
+		completed = true
+
+		return res, err
+	}
+}
+
+// State returns current state of circuit breaker.
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	cb.checkCoolDown()
+	return cb.state
+}
+
+// allow checks whether call can be made.
+// Returns generation of the state, which must be passed to report(), or ErrCircuitOpen.
+func (cb *CircuitBreaker) allow() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	cb.checkCoolDown()
+
+	switch cb.state {
+	case CircuitOpen:
+		return 0, ErrCircuitOpen
+	case CircuitHalfOpen:
+		if cb.probes >= cb.config.HalfOpenProbes {
+			return 0, ErrCircuitOpen
+		}
+		cb.probes++
+	}
+
+	return cb.generation, nil
+}
+
+// report result of the call allowed in the generation.
+func (cb *CircuitBreaker) report(generation uint64, success bool) {
+	cb.mu.Lock()
+	defer cb.unlock()
+
+	// Ignore calls started before the state has changed.
+	if generation != cb.generation {
+		return
+	}
+
+	switch cb.state {
+	case CircuitClosed:
+		bucket := cb.bucket()
+		if success {
+			bucket.successes++
+			cb.consecutiveFailures = 0
+			return
+		}
+		bucket.failures++
+		cb.consecutiveFailures++
+
+		if cb.tripped() {
+			cb.setState(CircuitOpen)
+		}
+	case CircuitHalfOpen:
+		if !success {
+			cb.setState(CircuitOpen)
+			return
+		}
+
+		cb.succeededProbes++
+		if cb.succeededProbes >= cb.config.HalfOpenProbes {
+			cb.setState(CircuitClosed)
+		}
+	}
+}
+
+// isFailure reports whether error is counted as failure.
+func (cb *CircuitBreaker) isFailure(err error) bool {
+	return cb.config.IsFailure == nil || cb.config.IsFailure(err)
+}
+
+// tripped reports whether failure thresholds are reached.
+// Must be called while holding the lock.
+func (cb *CircuitBreaker) tripped() bool {
+	if cb.config.ConsecutiveFailures > 0 && cb.consecutiveFailures >= cb.config.ConsecutiveFailures {
+		return true
+	}
+
+	if cb.config.FailureRate > 0 {
+		var successes, failures int
+		for _, bucket := range cb.buckets {
+			successes += bucket.successes
+			failures += bucket.failures
+		}
+
+		total := successes + failures
+		if total > 0 && total >= cb.config.MinRequests && float64(failures)/float64(total) >= cb.config.FailureRate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bucket returns the current bucket of rolling window, dropping buckets which are out of the window.
+// Must be called while holding the lock.
+func (cb *CircuitBreaker) bucket() *circuitBucket {
+	now := cb.clock.Now()
+
+	// Window is not required if only consecutive failures are counted.
+	if cb.config.Window <= 0 {
+		if len(cb.buckets) == 0 {
+			cb.buckets = append(cb.buckets, circuitBucket{start: now})
+		}
+		return &cb.buckets[0]
+	}
+
+	expired := 0
+	for expired < len(cb.buckets) && now.Sub(cb.buckets[expired].start) >= cb.config.Window {
+		expired++
+	}
+	cb.buckets = cb.buckets[expired:]
+
+	bucketDuration := cb.config.Window / circuitBreakerBuckets
+	if n := len(cb.buckets); n == 0 || now.Sub(cb.buckets[n-1].start) >= bucketDuration {
+		cb.buckets = append(cb.buckets, circuitBucket{start: now})
+	}
+
+	return &cb.buckets[len(cb.buckets)-1]
+}
+
+// checkCoolDown switches open circuit to half-open state once cool-down period elapses.
+// Must be called while holding the lock.
+func (cb *CircuitBreaker) checkCoolDown() {
+	if cb.state == CircuitOpen && !cb.clock.Now().Before(cb.openUntil) {
+		cb.setState(CircuitHalfOpen)
+	}
+}
+
+// setState switches circuit to the state and resets counters.
+// Must be called while holding the lock.
+func (cb *CircuitBreaker) setState(state CircuitState) {
+	cb.changes = append(cb.changes, circuitStateChange{from: cb.state, to: state})
+
+	cb.state = state
+	cb.generation++
+
+	cb.consecutiveFailures = 0
+	cb.buckets = nil
+	cb.probes = 0
+	cb.succeededProbes = 0
+
+	if state == CircuitOpen {
+		cb.openUntil = cb.clock.Now().Add(cb.config.CoolDown)
+	}
+}
+
+// unlock releases the lock and reports state changes made while holding it.
+func (cb *CircuitBreaker) unlock() {
+	changes := cb.changes
+	cb.changes = nil
+	cb.mu.Unlock()
+
+	if cb.config.OnStateChange != nil {
+		for _, change := range changes {
+			cb.config.OnStateChange(change.from, change.to)
+		}
+	}
+}
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
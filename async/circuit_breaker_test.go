@@ -0,0 +1,259 @@
+package async_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ fmt.Stringer = async.CircuitClosed
+)
+
+func ExampleCircuitBreaker() {
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 2,
+		CoolDown:            time.Minute,
+	})
+
+	call := async.Protect(cb, func() (string, error) {
+		// Call dependency, which is down.
+
+		return "", errors.New("dependency is down")
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := async.Execute(call)()
+		fmt.Println("Error:", err)
+	}
+
+	// Output:
+	// Error: dependency is down
+	// Error: dependency is down
+	// Error: circuit breaker is open
+}
+
+// circuitFunc returns function, which fails while *fail is true.
+func circuitFunc(fail *bool) func() (string, error) {
+	return func() (string, error) {
+		if *fail {
+			return "", dummyError
+		}
+		return "dummy result", nil
+	}
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	fail := true
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		CoolDown:            time.Second,
+		Clock:               newFakeClock(),
+	})
+	call := async.Protect(cb, circuitFunc(&fail))
+
+	// Success resets consecutive failures.
+	call()
+	call()
+	fail = false
+	call()
+	fail = true
+	call()
+	call()
+
+	if state := cb.State(); state != async.CircuitClosed {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+
+	if _, err := call(); !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error of the call: %#v", err)
+	}
+	if state := cb.State(); state != async.CircuitOpen {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+
+	fail = false
+	if _, err := call(); !errors.Is(err, async.ErrCircuitOpen) {
+		t.Errorf("Unexpected error of the call: %#v", err)
+	}
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	fail := false
+	clock := newFakeClock()
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      10 * time.Second,
+		CoolDown:    time.Second,
+		Clock:       clock,
+	})
+	call := async.Protect(cb, circuitFunc(&fail))
+
+	// Old calls leave the rolling window.
+	for i := 0; i < 3; i++ {
+		call()
+	}
+	clock.Advance(10 * time.Second)
+
+	call()
+	fail = true
+	call()
+	call()
+	if state := cb.State(); state != async.CircuitClosed {
+		t.Fatalf("Circuit opened before reaching minimum number of requests: %s", state)
+	}
+
+	call()
+	if state := cb.State(); state != async.CircuitOpen {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+}
+
+func TestCircuitBreaker_halfOpen(t *testing.T) {
+	fail := true
+	clock := newFakeClock()
+
+	var changes []string
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+		HalfOpenProbes:      2,
+		OnStateChange: func(from, to async.CircuitState) {
+			changes = append(changes, fmt.Sprintf("%s->%s", from, to))
+		},
+		Clock: clock,
+	})
+	call := async.Protect(cb, circuitFunc(&fail))
+
+	call()
+	clock.Advance(time.Second)
+
+	// Failed probe opens circuit again.
+	if state := cb.State(); state != async.CircuitHalfOpen {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+	call()
+	if state := cb.State(); state != async.CircuitOpen {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+
+	// Successful probes close circuit.
+	clock.Advance(time.Second)
+	fail = false
+	call()
+	call()
+	if state := cb.State(); state != async.CircuitClosed {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+
+	expected := []string{
+		"closed->open",
+		"open->half-open",
+		"half-open->open",
+		"open->half-open",
+		"half-open->closed",
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Unexpected state changes: %v", changes)
+	}
+}
+
+func TestCircuitBreaker_limitsProbes(t *testing.T) {
+	clock := newFakeClock()
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+		Clock:               clock,
+	})
+
+	async.Protect(cb, func() (string, error) { return "", dummyError })()
+	clock.Advance(time.Second)
+
+	// Probe is in progress, other calls are rejected.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	probe := async.Execute(async.Protect(cb, func() (string, error) {
+		close(started)
+		<-release
+		return "dummy result", nil
+	}))
+	<-started
+
+	if _, err := async.Protect(cb, func() (string, error) { return "", nil })(); !errors.Is(err, async.ErrCircuitOpen) {
+		t.Errorf("Unexpected error of the call: %#v", err)
+	}
+
+	close(release)
+	if _, err := probe(); err != nil {
+		t.Errorf("Unexpected error of the probe: %#v", err)
+	}
+	if state := cb.State(); state != async.CircuitClosed {
+		t.Errorf("Unexpected state of the circuit: %s", state)
+	}
+}
+
+func TestCircuitBreaker_IsFailure(t *testing.T) {
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+		IsFailure: func(err error) bool {
+			return !errors.Is(err, dummyError1)
+		},
+	})
+
+	async.Protect(cb, func() (string, error) { return "", dummyError1 })()
+	if state := cb.State(); state != async.CircuitClosed {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+
+	async.Protect(cb, func() (string, error) { return "", dummyError2 })()
+	if state := cb.State(); state != async.CircuitOpen {
+		t.Fatalf("Unexpected state of the circuit: %s", state)
+	}
+}
+
+func TestCircuitBreaker_countsPanics(t *testing.T) {
+	cb := async.NewCircuitBreaker(async.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+	})
+
+	_, err := async.Execute(async.Protect(cb, func() (string, error) {
+		panic(dummyError)
+	}))()
+
+	var panicErr *async.PanicError
+	if !errors.As(err, &panicErr) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+	if state := cb.State(); state != async.CircuitOpen {
+		t.Errorf("Unexpected state of the circuit: %s", state)
+	}
+}
+
+func TestNewCircuitBreaker_panicsOnInvalidConfig(t *testing.T) {
+	configs := []async.CircuitBreakerConfig{
+		{CoolDown: time.Second},
+		{ConsecutiveFailures: 1},
+		{FailureRate: 0.5, CoolDown: time.Second},
+		{FailureRate: 1.5, Window: time.Second, CoolDown: time.Second},
+	}
+
+	for _, config := range configs {
+		func() {
+			defer func() {
+				if err := recover(); err != async.ErrInvalidCircuitBreakerConfig {
+					t.Errorf("Unexpected panic for config %+v: %#v", config, err)
+				}
+			}()
+
+			async.NewCircuitBreaker(config)
+		}()
+	}
+}
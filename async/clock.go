@@ -0,0 +1,36 @@
+package async
+
+import (
+	"time"
+)
+
+// Clock provides current time and timers.
+// Allows replacing real time, e.g. to make tests deterministic.
+type Clock interface {
+	// Now returns current time.
+	Now() time.Time
+
+	// After returns channel which receives current time once duration d elapses.
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+// SystemClock is a Clock using real time.
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrDefault returns clock c or SystemClock if c is nil.
+func clockOrDefault(c Clock) Clock {
+	if c == nil {
+		return SystemClock
+	}
+	return c
+}
@@ -0,0 +1,70 @@
+package async_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// fakeClock is a Clock which advances time instantly when waiting.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	delays []time.Duration
+}
+
+// Ensure interface implementation
+var (
+	_ async.Clock = &fakeClock{}
+)
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.delays = append(c.delays, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Advance time by duration d without waiting.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Delays returns durations passed to After().
+func (c *fakeClock) Delays() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]time.Duration(nil), c.delays...)
+}
+
+func TestSystemClock(t *testing.T) {
+	start := async.SystemClock.Now()
+
+	<-async.SystemClock.After(time.Millisecond)
+
+	if elapsed := time.Since(start); elapsed < time.Millisecond {
+		t.Errorf("System clock did not wait: %s", elapsed)
+	}
+}
@@ -0,0 +1,151 @@
+package async
+
+import (
+	"context"
+)
+
+// Settled is an outcome of an asynchronous execution: either result or an encountered error.
+type Settled[T any] struct {
+	Value T
+	Err   error
+}
+
+type settleChannelMessageType[T any] struct {
+	executeChannelMessageType[T]
+	index int
+}
+
+// All waits for all promises.
+// Returns promise which will return results of all promises in the same order or the first encountered error.
+// On error, promise returns immediately without waiting for the remaining promises.
+//
+// See AllContext() for alternative which cancels remaining functions on error.
+func All[T any](promises ...PromiseWithError[T]) PromiseWithError[[]T] {
+	return Execute(func() ([]T, error) {
+		ch := settle(promises)
+
+		res := make([]T, len(promises))
+		for range promises {
+			msg := <-ch
+			value, err := msg.result()
+			if err != nil {
+				return nil, err
+			}
+			res[msg.index] = value
+		}
+
+		return res, nil
+	})
+}
+
+// AllContext executes all functions asynchronously with a shared context derived from ctx.
+// Returns promise which will return results of all functions in the same order or the first encountered error.
+// On error, shared context is cancelled to abort the remaining functions.
+func AllContext[T any](ctx context.Context, funcs ...func(context.Context) (T, error)) PromiseWithError[[]T] {
+	ctx, cancel := context.WithCancel(ctx)
+
+	promises := make([]PromiseWithError[T], len(funcs))
+	for i, f := range funcs {
+		promise, _ := ExecuteContext(ctx, f)
+		promises[i] = func() (T, error) { return promise(context.Background()) }
+	}
+
+	all := All(promises...)
+
+	return Execute(func() ([]T, error) {
+		// Abort the remaining functions as soon as result is known.
+		defer cancel()
+
+		return all()
+	})
+}
+
+// Any waits for the first successful promise.
+// Returns promise which will return result of the first successful promise.
+// If all promises fail, promise returns AggregatedError with all encountered errors.
+// If no promises are passed, promise returns ErrNoPromises.
+func Any[T any](promises ...PromiseWithError[T]) PromiseWithError[T] {
+	return Execute(func() (T, error) {
+		var res T
+		if len(promises) == 0 {
+			return res, ErrNoPromises
+		}
+
+		ch := settle(promises)
+
+		errs := make(AggregatedError, len(promises))
+		for range promises {
+			msg := <-ch
+			value, err := msg.result()
+			if err == nil {
+				return value, nil
+			}
+			errs[msg.index] = err
+		}
+
+		return res, errs
+	})
+}
+
+// Race waits for the first promise to finish.
+// Returns promise which will return result or error of the first finished promise.
+// If no promises are passed, promise returns ErrNoPromises.
+func Race[T any](promises ...PromiseWithError[T]) PromiseWithError[T] {
+	return Execute(func() (T, error) {
+		if len(promises) == 0 {
+			var res T
+			return res, ErrNoPromises
+		}
+
+		msg := <-settle(promises)
+		return msg.result()
+	})
+}
+
+// AllSettled waits for all promises.
+// Returns promise which will return results and errors of all promises in the same order.
+//
+// Promise can be called only once.
+// Calling promise repeatedly will result in panic.
+func AllSettled[T any](promises ...PromiseWithError[T]) Promise[[]Settled[T]] {
+	promise := Execute(func() ([]Settled[T], error) {
+		ch := settle(promises)
+
+		res := make([]Settled[T], len(promises))
+		for range promises {
+			msg := <-ch
+			value, err := msg.result()
+			res[msg.index] = Settled[T]{Value: value, Err: err}
+		}
+
+		return res, nil
+	})
+
+	return func() []Settled[T] {
+		// Error is returned only when promise is called repeatedly, since panics of promises are stored in results.
+		res, err := promise()
+		if err != nil {
+			panic(err)
+		}
+		return res
+	}
+}
+
+// settle calls all promises asynchronously.
+// Returns channel which will receive result of each promise as soon as it is available.
+func settle[T any](promises []PromiseWithError[T]) <-chan settleChannelMessageType[T] {
+	// This channel is buffered to fit all results.
+	// That way all goroutines will end even if not all results are received.
+	ch := make(chan settleChannelMessageType[T], len(promises))
+
+	for i, promise := range promises {
+		go func(i int, promise PromiseWithError[T]) {
+			// Promise is called via execute() to handle panics.
+			// Panic is raised again by the receiver if panic policy requires so.
+			msg := <-execute(taskName(promise), promise)
+			ch <- settleChannelMessageType[T]{executeChannelMessageType: msg, index: i}
+		}(i, promise)
+	}
+
+	return ch
+}
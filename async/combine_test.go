@@ -0,0 +1,256 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleAll() {
+	promise := async.All(
+		async.Execute(func() (string, error) {
+			return "string result of 1st lengthy operation", nil
+		}),
+		async.Execute(func() (string, error) {
+			return "string result of 2nd lengthy operation", nil
+		}),
+	)
+
+	res, err := promise()
+	fmt.Println("Result:", res)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Result: [string result of 1st lengthy operation string result of 2nd lengthy operation]
+	// Error: <nil>
+}
+
+func ExampleAllSettled() {
+	promise := async.AllSettled(
+		async.Execute(func() (string, error) {
+			return "string result of 1st lengthy operation", nil
+		}),
+		async.Execute(func() (string, error) {
+			return "", errors.New("2nd lengthy operation failed")
+		}),
+	)
+
+	for _, res := range promise() {
+		fmt.Printf("Result: %q, error: %v\n", res.Value, res.Err)
+	}
+
+	// Output:
+	// Result: "string result of 1st lengthy operation", error: <nil>
+	// Result: "", error: 2nd lengthy operation failed
+}
+
+func TestAll_passesResultsInOrder(t *testing.T) {
+	promise := async.All(
+		async.Execute(func() (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 1, nil
+		}),
+		async.Execute(func() (int, error) {
+			return 2, nil
+		}),
+	)
+
+	res, err := promise()
+	if !reflect.DeepEqual(res, []int{1, 2}) {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAll_returnsFirstErrorWithoutWaiting(t *testing.T) {
+	chRelease := make(chan struct{})
+	defer close(chRelease)
+
+	promise := async.All(
+		async.Execute(func() (int, error) {
+			<-chRelease
+			return 1, nil
+		}),
+		async.Execute(func() (int, error) {
+			return 0, dummyError
+		}),
+	)
+
+	res, err := promise()
+	if res != nil {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAll_handlesPanics(t *testing.T) {
+	promise := async.All(func() (int, error) {
+		panic(dummyError)
+	})
+
+	if _, err := promise(); !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAllContext_cancelsRemainingFunctionsOnError(t *testing.T) {
+	chCancelled := make(chan error, 1)
+
+	promise := async.AllContext(context.TODO(),
+		func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			chCancelled <- ctx.Err()
+			return 0, ctx.Err()
+		},
+		func(ctx context.Context) (int, error) {
+			return 0, dummyError
+		},
+	)
+
+	if _, err := promise(); !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	if err := <-chCancelled; !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the remaining function: %#v", err)
+	}
+}
+
+func TestAllContext_passesResultsInOrder(t *testing.T) {
+	promise := async.AllContext(context.TODO(),
+		func(ctx context.Context) (int, error) {
+			return 1, nil
+		},
+		func(ctx context.Context) (int, error) {
+			return 2, nil
+		},
+	)
+
+	res, err := promise()
+	if !reflect.DeepEqual(res, []int{1, 2}) {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAny_returnsFirstSuccess(t *testing.T) {
+	chRelease := make(chan struct{})
+	defer close(chRelease)
+
+	promise := async.Any(
+		async.Execute(func() (int, error) {
+			return 0, dummyError1
+		}),
+		async.Execute(func() (int, error) {
+			<-chRelease
+			return 1, nil
+		}),
+		async.Execute(func() (int, error) {
+			return 2, nil
+		}),
+	)
+
+	res, err := promise()
+	if res != 2 {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAny_aggregatesErrorsWhenAllFail(t *testing.T) {
+	promise := async.Any(
+		async.Execute(func() (int, error) {
+			return 0, dummyError1
+		}),
+		async.Execute(func() (int, error) {
+			return 0, dummyError2
+		}),
+	)
+
+	_, err := promise()
+
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if len(aggregatedError) != 2 || aggregatedError[0] != dummyError1 || aggregatedError[1] != dummyError2 {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+}
+
+func TestAny_withoutPromisesReturnsError(t *testing.T) {
+	if _, err := async.Any[int]()(); !errors.Is(err, async.ErrNoPromises) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestRace_returnsFirstSettled(t *testing.T) {
+	chRelease := make(chan struct{})
+	defer close(chRelease)
+
+	promise := async.Race(
+		async.Execute(func() (int, error) {
+			<-chRelease
+			return 1, nil
+		}),
+		async.Execute(func() (int, error) {
+			return 0, dummyError
+		}),
+	)
+
+	res, err := promise()
+	if res != 0 {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestRace_withoutPromisesReturnsError(t *testing.T) {
+	if _, err := async.Race[int]()(); !errors.Is(err, async.ErrNoPromises) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestAllSettled_passesResultsAndErrorsInOrder(t *testing.T) {
+	promise := async.AllSettled(
+		async.Execute(func() (int, error) {
+			time.Sleep(10 * time.Millisecond)
+			return 1, nil
+		}),
+		async.Execute(func() (int, error) {
+			return 2, dummyError
+		}),
+		func() (int, error) {
+			panic(dummyError)
+		},
+	)
+
+	res := promise()
+	if len(res) != 3 {
+		t.Fatalf("Unexpected number of results received from the promise: %#v", res)
+	}
+	if res[0].Value != 1 || res[0].Err != nil {
+		t.Errorf("Unexpected 1st result received from the promise: %#v", res[0])
+	}
+	if res[1].Value != 2 || !errors.Is(res[1].Err, dummyError) {
+		t.Errorf("Unexpected 2nd result received from the promise: %#v", res[1])
+	}
+	if !errors.Is(res[2].Err, dummyError) {
+		t.Errorf("Unexpected 3rd result received from the promise: %#v", res[2])
+	}
+}
@@ -0,0 +1,242 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DAG is a set of tasks with dependencies between them (directed acyclic graph).
+// Method Execute() launches each task as soon as all its dependencies have succeeded.
+// Tasks, which dependencies have failed, are skipped.
+type DAG struct {
+	tasks    []*dagTask
+	executed bool
+	limit    int
+}
+
+// DAGNode is a task of DAG, which other tasks can depend on.
+type DAGNode interface {
+	node() *dagTask
+}
+
+// DAGTask is a task of DAG, which produces result of type T.
+type DAGTask[T any] struct {
+	task *dagTask
+	res  T
+}
+
+// TaskError is an error of a DAG task.
+type TaskError struct {
+	Task string
+	Err  error
+}
+
+type dagTask struct {
+	dag  *DAG
+	name string
+	deps []*dagTask
+	run  func(context.Context) error
+
+	// Channel is closed when task completes, failed flag is set before that.
+	done   chan struct{}
+	failed bool
+}
+
+// AddToDAG registers function f as a named task of DAG, which depends on tasks deps.
+// Function f is called only after all dependencies have succeeded, so it can use their results (see DAGTask.Result()).
+func AddToDAG[T any](dag *DAG, name string, f func(context.Context) (T, error), deps ...DAGNode) *DAGTask[T] {
+	t := &DAGTask[T]{}
+
+	task := taskName(f)
+	t.task = &dagTask{
+		dag:  dag,
+		name: name,
+		done: make(chan struct{}),
+		run: func(ctx context.Context) error {
+			msg := call(task, currentPanicPolicy(), func() (T, error) { return f(ctx) })
+			t.res = msg.res
+
+			// Raise panic again, so group handles it according to panic policy.
+			if msg.repanic {
+				panic(msg.err)
+			}
+			return msg.err
+		},
+	}
+	t.After(deps...)
+
+	dag.tasks = append(dag.tasks, t.task)
+	return t
+}
+
+// After adds dependencies to the task.
+// Returns the task itself for chaining.
+func (t *DAGTask[T]) After(deps ...DAGNode) *DAGTask[T] {
+	for _, dep := range deps {
+		t.task.deps = append(t.task.deps, dep.node())
+	}
+	return t
+}
+
+// Result returns result of the task.
+// Result is available to the tasks depending on this one and after DAG was executed.
+func (t *DAGTask[T]) Result() T {
+	return t.res
+}
+
+func (t *DAGTask[T]) node() *dagTask {
+	return t.task
+}
+
+// SetLimit limits the number of tasks executing at the same time to n.
+// Zero or negative n removes the limit.
+//
+// SetLimit must be called before Execute().
+func (d *DAG) SetLimit(n int) {
+	d.limit = n
+}
+
+// Validate checks that there are no cycles between tasks and that all dependencies belong to this DAG.
+func (d *DAG) Validate() error {
+	_, err := d.sort()
+	return err
+}
+
+// Execute tasks of DAG.
+// Will block until all tasks have executed or were skipped, and will return aggregated errors.
+// Each error is TaskError, skipped tasks have ErrDependencyFailed error.
+// If DAG is not valid, returns its validation error without executing any tasks.
+//
+// Execute can be called only once.
+// Calling Execute() repeatedly will result in an error.
+func (d *DAG) Execute(ctx context.Context) AggregatedError {
+	if d.executed {
+		return AggregatedError{ErrDAGAlreadyExecuted}
+	}
+	d.executed = true
+
+	tasks, err := d.sort()
+	if err != nil {
+		return AggregatedError{err}
+	}
+
+	// Tasks are added to the group in topological order,
+	// so limited group launches all dependencies before their dependents and never blocks.
+	var group Group
+	group.SetLimit(d.limit)
+
+	for _, task := range tasks {
+		task := task
+
+		AddToExecutionGroup(&group, func() (res struct{}, err error) {
+			// Make sure dependents are notified even if task panics.
+			completed := false
+			defer func() {
+				task.failed = !completed || err != nil
+				close(task.done)
+			}()
+
+			err = task.execute(ctx)
+			completed = true
+
+			return res, err
+		})
+	}
+
+	return group.Execute()
+}
+
+// execute task after its dependencies have completed.
+// Returns ErrDependencyFailed without executing the task if any of dependencies has failed.
+func (t *dagTask) execute(ctx context.Context) error {
+	for _, dep := range t.deps {
+		<-dep.done
+		if dep.failed {
+			return TaskError{Task: t.name, Err: ErrDependencyFailed}
+		}
+	}
+
+	if err := t.run(ctx); err != nil {
+		return TaskError{Task: t.name, Err: err}
+	}
+	return nil
+}
+
+// sort tasks in topological order, preserving the order in which tasks were added where possible.
+// Returns error if there is a cycle or a dependency from another DAG.
+func (d *DAG) sort() ([]*dagTask, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[*dagTask]int, len(d.tasks))
+	sorted := make([]*dagTask, 0, len(d.tasks))
+	var path []*dagTask
+
+	var visit func(task *dagTask) error
+	visit = func(task *dagTask) error {
+		if task.dag != d {
+			return fmt.Errorf("%w: %q", ErrDAGForeignTask, task.name)
+		}
+
+		switch state[task] {
+		case visited:
+			return nil
+		case visiting:
+			return cycleError(path, task)
+		}
+
+		state[task] = visiting
+		path = append(path, task)
+
+		for _, dep := range task.deps {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[task] = visited
+		sorted = append(sorted, task)
+
+		return nil
+	}
+
+	for _, task := range d.tasks {
+		if err := visit(task); err != nil {
+			return nil, err
+		}
+	}
+
+	return sorted, nil
+}
+
+// cycleError describes cycle, which starts and ends with the task.
+func cycleError(path []*dagTask, task *dagTask) error {
+	start := 0
+	for i, t := range path {
+		if t == task {
+			start = i
+		}
+	}
+
+	var names []string
+	for _, t := range path[start:] {
+		names = append(names, fmt.Sprintf("%q", t.name))
+	}
+	names = append(names, fmt.Sprintf("%q", task.name))
+
+	return fmt.Errorf("%w: %s", ErrDAGCycle, strings.Join(names, " depends on "))
+}
+
+func (e TaskError) Error() string {
+	return fmt.Sprintf("task %q: %s", e.Task, e.Err.Error())
+}
+
+// Unwrap returns the original error.
+func (e TaskError) Unwrap() error {
+	return e.Err
+}
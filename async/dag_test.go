@@ -0,0 +1,246 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ error         = async.TaskError{}
+	_ async.DAGNode = &async.DAGTask[int]{}
+)
+
+func ExampleDAG() {
+	var dag async.DAG
+
+	config := async.AddToDAG(&dag, "config", func(ctx context.Context) (string, error) {
+		// Load configuration.
+
+		return "postgres://localhost/db", nil
+	})
+
+	db := async.AddToDAG(&dag, "database", func(ctx context.Context) (string, error) {
+		// Connect to the database using configuration.
+
+		return fmt.Sprintf("connection to %s", config.Result()), nil
+	}, config)
+
+	if err := dag.Execute(context.TODO()); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("Database:", db.Result())
+
+	// Output:
+	// Database: connection to postgres://localhost/db
+}
+
+func TestDAG_executesDependenciesFirst(t *testing.T) {
+	var dag async.DAG
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	a := async.AddToDAG(&dag, "a", func(ctx context.Context) (int, error) {
+		time.Sleep(5 * time.Millisecond)
+		record("a")
+		return 1, nil
+	})
+	b := async.AddToDAG(&dag, "b", func(ctx context.Context) (int, error) {
+		record("b")
+		return 2, nil
+	})
+	c := async.AddToDAG(&dag, "c", func(ctx context.Context) (int, error) {
+		record("c")
+		return a.Result() + b.Result(), nil
+	}, a, b)
+	d := async.AddToDAG(&dag, "d", func(ctx context.Context) (int, error) {
+		record("d")
+		return c.Result() * 10, nil
+	}, c)
+
+	if err := dag.Execute(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error received from DAG: %#v", err)
+	}
+
+	if res := d.Result(); res != 30 {
+		t.Errorf("Unexpected result of the task: %#v", res)
+	}
+	if len(order) != 4 || order[2] != "c" || order[3] != "d" {
+		t.Errorf("Unexpected order of execution: %v", order)
+	}
+}
+
+func TestDAG_runsIndependentTasksConcurrently(t *testing.T) {
+	var dag async.DAG
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	for _, name := range []string{"a", "b"} {
+		async.AddToDAG(&dag, name, func(ctx context.Context) (interface{}, error) {
+			wg.Done()
+			wg.Wait()
+			return nil, nil
+		})
+	}
+
+	if err := dag.Execute(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received from DAG: %#v", err)
+	}
+}
+
+func TestDAG_skipsDependentsOfFailedTasks(t *testing.T) {
+	var dag async.DAG
+
+	a := async.AddToDAG(&dag, "a", func(ctx context.Context) (int, error) {
+		return 0, dummyError
+	})
+	b := async.AddToDAG(&dag, "b", func(ctx context.Context) (int, error) {
+		t.Error("Dependent of failed task was executed")
+		return 0, nil
+	}, a)
+	async.AddToDAG(&dag, "c", func(ctx context.Context) (int, error) {
+		t.Error("Transitive dependent of failed task was executed")
+		return 0, nil
+	}, b)
+	async.AddToDAG(&dag, "d", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	err := dag.Execute(context.TODO())
+	if len(err) != 3 {
+		t.Fatalf("Expected aggregated error to have 3 errors, but got: %#v", err)
+	}
+
+	failed := make(map[string]error)
+	for _, e := range err {
+		var taskError async.TaskError
+		if !errors.As(e, &taskError) {
+			t.Fatalf("Unexpected error received from DAG: %#v", e)
+		}
+		failed[taskError.Task] = taskError.Err
+	}
+
+	if !errors.Is(failed["a"], dummyError) {
+		t.Errorf("Unexpected error of task a: %#v", failed["a"])
+	}
+	if !errors.Is(failed["b"], async.ErrDependencyFailed) {
+		t.Errorf("Unexpected error of task b: %#v", failed["b"])
+	}
+	if !errors.Is(failed["c"], async.ErrDependencyFailed) {
+		t.Errorf("Unexpected error of task c: %#v", failed["c"])
+	}
+}
+
+func TestDAG_skipsDependentsOfPanickedTasks(t *testing.T) {
+	var dag async.DAG
+
+	a := async.AddToDAG(&dag, "a", func(ctx context.Context) (int, error) {
+		panic(dummyError)
+	})
+	async.AddToDAG(&dag, "b", func(ctx context.Context) (int, error) {
+		t.Error("Dependent of panicked task was executed")
+		return 0, nil
+	}, a)
+
+	err := dag.Execute(context.TODO())
+	if len(err) != 2 {
+		t.Fatalf("Expected aggregated error to have 2 errors, but got: %#v", err)
+	}
+
+	var panicError *async.PanicError
+	if !err.Find(&panicError) {
+		t.Errorf("Expected aggregated error to contain panic error: %#v", err)
+	}
+	if !err.Has(async.ErrDependencyFailed) {
+		t.Errorf("Expected aggregated error to contain skipped task: %#v", err)
+	}
+}
+
+func TestDAG_detectsCycles(t *testing.T) {
+	var dag async.DAG
+
+	a := async.AddToDAG(&dag, "a", func(ctx context.Context) (int, error) {
+		t.Error("Task of invalid DAG was executed")
+		return 0, nil
+	})
+	b := async.AddToDAG(&dag, "b", func(ctx context.Context) (int, error) {
+		t.Error("Task of invalid DAG was executed")
+		return 0, nil
+	}, a)
+	a.After(b)
+
+	err := dag.Validate()
+	if !errors.Is(err, async.ErrDAGCycle) {
+		t.Fatalf("Unexpected validation error: %#v", err)
+	}
+	if err.Error() != `DAG has a cycle: "a" depends on "b" depends on "a"` {
+		t.Errorf("Unexpected validation error message: %s", err)
+	}
+
+	if err := dag.Execute(context.TODO()); !err.Has(async.ErrDAGCycle) {
+		t.Errorf("Unexpected error received from DAG: %#v", err)
+	}
+}
+
+func TestDAG_detectsForeignTasks(t *testing.T) {
+	var dag1, dag2 async.DAG
+
+	a := async.AddToDAG(&dag1, "a", func(ctx context.Context) (int, error) {
+		return 0, nil
+	})
+	async.AddToDAG(&dag2, "b", func(ctx context.Context) (int, error) {
+		return 0, nil
+	}, a)
+
+	if err := dag2.Validate(); !errors.Is(err, async.ErrDAGForeignTask) {
+		t.Errorf("Unexpected validation error: %#v", err)
+	}
+}
+
+func TestDAG_SetLimit(t *testing.T) {
+	var dag async.DAG
+	dag.SetLimit(1)
+
+	// Dependent task is added before its dependency, but limited DAG still completes.
+	var a *async.DAGTask[int]
+	b := async.AddToDAG(&dag, "b", func(ctx context.Context) (int, error) {
+		return a.Result() + 1, nil
+	})
+	a = async.AddToDAG(&dag, "a", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	b.After(a)
+
+	if err := dag.Execute(context.TODO()); err != nil {
+		t.Fatalf("Unexpected error received from DAG: %#v", err)
+	}
+	if res := b.Result(); res != 2 {
+		t.Errorf("Unexpected result of the task: %#v", res)
+	}
+}
+
+func TestDAG_callingExecuteRepeatedlyReturnsError(t *testing.T) {
+	var dag async.DAG
+
+	if err := dag.Execute(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received from DAG: %#v", err)
+	}
+	if err := dag.Execute(context.TODO()); !err.Has(async.ErrDAGAlreadyExecuted) {
+		t.Errorf("Unexpected error received from DAG: %#v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Dedup deduplicates concurrent asynchronous calls for the same key.
+// While call for a key is in flight, subsequent calls for the same key join it and receive the same result.
+// Zero value is ready to use.
+type Dedup[K comparable, T any] struct {
+	mu    sync.Mutex
+	calls map[K]*dedupCall[T]
+}
+
+type dedupCall[T any] struct {
+	future  *Future[T]
+	cancel  context.CancelFunc
+	waiters int
+
+	// Total number of callers, which have joined the call.
+	callers int
+}
+
+// Do executes function f asynchronously, unless there is an in-flight call for the same key, in which case it joins that call.
+// Returns promise which can be called to retrieve the shared result and whether call has joined an in-flight call.
+// See DoShared() to learn whether result was shared, including by the caller which has started the call.
+//
+// Function f receives its own context, which is not derived from ctx.
+// It is cancelled only when every caller sharing the call has given up, i.e. their contexts are done before result is available.
+// Promise returns caller's context error in this case.
+//
+// Unlike promise returned by Execute(), this promise can be called repeatedly.
+func (d *Dedup[K, T]) Do(ctx context.Context, key K, f func(context.Context) (T, error)) (PromiseWithError[T], bool) {
+	call, joined := d.join(ctx, key, f)
+
+	return func() (T, error) {
+		return call.future.AwaitContext(ctx)
+	}, joined
+}
+
+// DoShared is the same as Do(), but returned promise also reports whether the result was shared with other callers.
+// Unlike joined flag returned by Do(), it is reported to the caller which has started the call as well.
+func (d *Dedup[K, T]) DoShared(ctx context.Context, key K, f func(context.Context) (T, error)) func() (T, bool, error) {
+	call, _ := d.join(ctx, key, f)
+
+	return func() (T, bool, error) {
+		res, err := call.future.AwaitContext(ctx)
+		if !call.future.IsDone() {
+			return res, false, err
+		}
+
+		// Call is not shared anymore once it completes, so the number of callers is final.
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		return res, call.callers > 1, err
+	}
+}
+
+// join the in-flight call for the key or start a new one.
+// Reports whether in-flight call was joined.
+func (d *Dedup[K, T]) join(ctx context.Context, key K, f func(context.Context) (T, error)) (*dedupCall[T], bool) {
+	d.mu.Lock()
+
+	call, joined := d.calls[key]
+	if !joined {
+		call = d.start(key, f)
+	}
+	call.waiters++
+	call.callers++
+
+	d.mu.Unlock()
+
+	// Track when caller gives up waiting.
+	go func() {
+		select {
+		case <-call.future.Done():
+		case <-ctx.Done():
+			d.leave(key, call)
+		}
+	}()
+
+	return call, joined
+}
+
+// Forget key, so that the next call for it starts a new execution even if current call is still in flight.
+// Callers which have already joined current call still receive its result.
+func (d *Dedup[K, T]) Forget(key K) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.calls, key)
+}
+
+// start executing function f for the key.
+// Must be called while holding the lock.
+func (d *Dedup[K, T]) start(key K, f func(context.Context) (T, error)) *dedupCall[T] {
+	if d.calls == nil {
+		d.calls = make(map[K]*dedupCall[T])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	call := &dedupCall[T]{cancel: cancel}
+
+	call.future = executeFuture(taskName(f), func() (T, error) {
+		// Release context resources and stop sharing the call as soon as function f completes.
+		defer cancel()
+		defer d.remove(key, call)
+
+		return f(ctx)
+	})
+
+	d.calls[key] = call
+	return call
+}
+
+// leave the call when caller gives up waiting.
+// Cancels the call if nobody is waiting for it anymore.
+func (d *Dedup[K, T]) leave(key K, call *dedupCall[T]) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+
+		// Cancelled call must not be joined by new callers.
+		if d.calls[key] == call {
+			delete(d.calls, key)
+		}
+	}
+}
+
+// remove the call, so it is not shared anymore.
+func (d *Dedup[K, T]) remove(key K, call *dedupCall[T]) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Call might have been forgotten and replaced by a new one.
+	if d.calls[key] == call {
+		delete(d.calls, key)
+	}
+}
@@ -0,0 +1,236 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleDedup() {
+	var dedup async.Dedup[string, string]
+
+	chRelease := make(chan struct{})
+	load := func(ctx context.Context) (string, error) {
+		// Perform some lengthy operation, e.g. load value from backend.
+		<-chRelease
+
+		return "value loaded from backend", nil
+	}
+
+	promise1, joined1 := dedup.Do(context.TODO(), "key", load)
+	promise2, joined2 := dedup.Do(context.TODO(), "key", load)
+	close(chRelease)
+
+	res1, _ := promise1()
+	res2, _ := promise2()
+
+	fmt.Println("1st result:", res1, "joined:", joined1)
+	fmt.Println("2nd result:", res2, "joined:", joined2)
+
+	// Output:
+	// 1st result: value loaded from backend joined: false
+	// 2nd result: value loaded from backend joined: true
+}
+
+func TestDedup_sharesExecution(t *testing.T) {
+	var dedup async.Dedup[string, string]
+
+	var calls int32
+	chRelease := make(chan struct{})
+
+	f := func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		<-chRelease
+		return "dummy result", dummyError
+	}
+
+	var promises []async.PromiseWithError[string]
+	for i := 0; i < 10; i++ {
+		promise, _ := dedup.Do(context.TODO(), "key", f)
+		promises = append(promises, promise)
+	}
+
+	// Different key is not shared.
+	other, joined := dedup.Do(context.TODO(), "other key", f)
+	if joined {
+		t.Error("Call for a different key has joined in-flight call")
+	}
+
+	close(chRelease)
+
+	for _, promise := range append(promises, other) {
+		res, err := promise()
+		if res != "dummy result" {
+			t.Errorf("Unexpected result received from the promise: %#v", res)
+		}
+		if !errors.Is(err, dummyError) {
+			t.Errorf("Unexpected error received from the promise: %#v", err)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 2 {
+		t.Errorf("Function was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestDedup_DoShared(t *testing.T) {
+	var dedup async.Dedup[string, string]
+
+	chRelease := make(chan struct{})
+	f := func(ctx context.Context) (string, error) {
+		<-chRelease
+		return "dummy result", nil
+	}
+
+	leader := dedup.DoShared(context.TODO(), "key", f)
+	follower := dedup.DoShared(context.TODO(), "key", f)
+	alone := dedup.DoShared(context.TODO(), "other key", f)
+
+	close(chRelease)
+
+	// Both the caller which has started the call and the one which has joined it learn that result was shared.
+	for _, promise := range []func() (string, bool, error){leader, follower} {
+		if res, shared, err := promise(); res != "dummy result" || !shared || err != nil {
+			t.Errorf("Unexpected result received from the promise: %#v, %#v, %#v", res, shared, err)
+		}
+	}
+	if res, shared, err := alone(); res != "dummy result" || shared || err != nil {
+		t.Errorf("Unexpected result received from the promise: %#v, %#v, %#v", res, shared, err)
+	}
+}
+
+func TestDedup_startsNewExecutionAfterCompletion(t *testing.T) {
+	var dedup async.Dedup[string, int]
+
+	var calls int32
+	f := func(ctx context.Context) (int, error) {
+		return int(atomic.AddInt32(&calls, 1)), nil
+	}
+
+	for i := 1; i <= 2; i++ {
+		promise, joined := dedup.Do(context.TODO(), "key", f)
+		if joined {
+			t.Error("Call has joined already completed call")
+		}
+		if res, _ := promise(); res != i {
+			t.Errorf("Unexpected result received from the promise: %#v", res)
+		}
+	}
+}
+
+func TestDedup_Forget(t *testing.T) {
+	var dedup async.Dedup[string, string]
+
+	chRelease := make(chan struct{})
+
+	first, _ := dedup.Do(context.TODO(), "key", func(ctx context.Context) (string, error) {
+		<-chRelease
+		return "dummy result 1", nil
+	})
+
+	dedup.Forget("key")
+
+	second, joined := dedup.Do(context.TODO(), "key", func(ctx context.Context) (string, error) {
+		return "dummy result 2", nil
+	})
+	if joined {
+		t.Error("Call has joined forgotten call")
+	}
+	if res, _ := second(); res != "dummy result 2" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+
+	// Forgotten call still delivers its result to the callers which have joined it.
+	close(chRelease)
+
+	if res, _ := first(); res != "dummy result 1" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+}
+
+func TestDedup_cancelsOnlyWhenAllWaitersGiveUp(t *testing.T) {
+	var dedup async.Dedup[string, string]
+
+	chCancelled := make(chan struct{})
+	f := func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		close(chCancelled)
+		return "", ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.TODO())
+	defer cancel1()
+	ctx2, cancel2 := context.WithCancel(context.TODO())
+	defer cancel2()
+
+	promise1, _ := dedup.Do(ctx1, "key", f)
+	promise2, _ := dedup.Do(ctx2, "key", f)
+
+	cancel1()
+	if _, err := promise1(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	select {
+	case <-chCancelled:
+		t.Fatal("Shared call was cancelled while another caller is still waiting")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel2()
+	if _, err := promise2(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	<-chCancelled
+
+	// Cancelled call is not joined by new callers.
+	promise3, joined := dedup.Do(context.TODO(), "key", func(ctx context.Context) (string, error) {
+		return "dummy result", nil
+	})
+	if joined {
+		t.Error("Call has joined cancelled call")
+	}
+	if res, err := promise3(); res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result received from the promise: %#v, %#v", res, err)
+	}
+}
+
+func TestDedup_concurrentCallers(t *testing.T) {
+	var dedup async.Dedup[int, int]
+
+	var calls int32
+	chRelease := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(100)
+
+	for i := 0; i < 100; i++ {
+		go func(key int) {
+			defer wg.Done()
+
+			promise, _ := dedup.Do(context.TODO(), key, func(ctx context.Context) (int, error) {
+				atomic.AddInt32(&calls, 1)
+				<-chRelease
+				return key, nil
+			})
+			if res, err := promise(); res != key || err != nil {
+				t.Errorf("Unexpected result received from the promise: %#v, %#v", res, err)
+			}
+		}(i % 2)
+	}
+
+	time.Sleep(time.Millisecond)
+	close(chRelease)
+	wg.Wait()
+
+	if calls := atomic.LoadInt32(&calls); calls < 2 {
+		t.Errorf("Function was called unexpected number of times: %d", calls)
+	}
+}
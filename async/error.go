@@ -6,6 +6,26 @@ import (
 
 // Predefined errors.
 var (
-	ErrPromiseAlreadyExecuted = errors.New("promise was already executed, calling promise multiple times is not supported")
-	ErrGroupAlreadyExecuted   = errors.New("group was already executed, calling Execute() on the same group multiple times is not supported")
+	ErrPromiseAlreadyExecuted      = errors.New("promise was already executed, calling promise multiple times is not supported")
+	ErrGroupAlreadyExecuted        = errors.New("group was already executed, calling Execute() on the same group multiple times is not supported")
+	ErrNoPromises                  = errors.New("no promises were passed")
+	ErrInvalidPoolConfig           = errors.New("pool must have at least 1 worker and non-negative queue size")
+	ErrPoolClosed                  = errors.New("pool was shut down")
+	ErrPoolQueueFull               = errors.New("pool queue is full")
+	ErrInvalidCacheConfig          = errors.New("cache must have loader and non-negative max size")
+	ErrDAGAlreadyExecuted          = errors.New("DAG was already executed, calling Execute() on the same DAG multiple times is not supported")
+	ErrDAGCycle                    = errors.New("DAG has a cycle")
+	ErrDAGForeignTask              = errors.New("DAG task depends on a task from another DAG")
+	ErrDependencyFailed            = errors.New("dependency failed, task was skipped")
+	ErrCircuitOpen                 = errors.New("circuit breaker is open")
+	ErrInvalidCircuitBreakerConfig = errors.New("circuit breaker must have failure threshold and positive cool-down period")
+	ErrInvalidRateLimiterConfig    = errors.New("rate limiter must have positive limit and interval")
+	ErrReservationExceedsLimit     = errors.New("reservation exceeds rate limiter's capacity")
+	ErrInvalidSemaphoreSize        = errors.New("semaphore size must be greater than 0")
+	ErrSemaphoreWeightExceedsSize  = errors.New("requested weight exceeds semaphore size")
+	ErrInvalidSemaphoreWeight      = errors.New("semaphore weight must be greater than 0")
+	ErrSemaphoreReleasedTooMuch    = errors.New("semaphore released more weight than acquired")
+	ErrInvalidLatchCount           = errors.New("latch count must not be negative")
+	ErrInvalidBarrierParties       = errors.New("barrier must have at least 1 party")
+	ErrMutexNotLocked              = errors.New("unlock of unlocked mutex")
 )
@@ -1,12 +1,15 @@
 package async
 
 import (
-	"fmt"
+	"context"
 )
 
 type executeChannelMessageType[T any] struct {
 	res T
 	err error
+
+	// Error is a panic, which must be raised again when result is awaited.
+	repanic bool
 }
 
 // Execute function f asynchronously.
@@ -17,7 +20,51 @@ type executeChannelMessageType[T any] struct {
 // Calling promise repeatedly will result in an error.
 //
 // It is advisable to use context to cancel function's f execution (see example code).
+// See ExecuteContext() for context aware alternative.
 func Execute[T any](f func() (T, error)) PromiseWithError[T] {
+	return newPromise(execute(taskName(f), f))
+}
+
+// ExecuteContext executes function f asynchronously with a context derived from ctx.
+// Returns promise which can be called to retrieve function's f result and a function which cancels derived context.
+// Calling promise will block execution until function f returns result or until context passed to the promise is done.
+// Derived context is cancelled automatically when function f returns.
+//
+// If context passed to the promise is done first, promise returns context's error and can be called again later.
+// Once result is received, promise can not be called again.
+// Calling promise repeatedly will result in an error.
+func ExecuteContext[T any](ctx context.Context, f func(context.Context) (T, error)) (PromiseWithContext[T], context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	ch := execute(taskName(f), func() (T, error) {
+		// Release context resources as soon as function f completes.
+		defer cancel()
+
+		return f(ctx)
+	})
+
+	return func(ctx context.Context) (T, error) {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				msg.err = ErrPromiseAlreadyExecuted
+			}
+
+			return msg.result()
+		case <-ctx.Done():
+			var res T
+			return res, ctx.Err()
+		}
+	}, cancel
+}
+
+// execute function f asynchronously.
+// Returns channel which will receive function's f result exactly once and will be closed afterwards.
+// Task is the name of the function to be reported if it panics.
+// Panics are handled according to the package level panic policy.
+func execute[T any](task string, f func() (T, error)) <-chan executeChannelMessageType[T] {
+	policy := currentPanicPolicy()
+
 	// This channel is buffered. It will be written to only once.
 	// That way when function f completes, goroutine will end as well (even if promise is never called and channel not drained).
 	ch := make(chan executeChannelMessageType[T], 1)
@@ -26,35 +73,49 @@ func Execute[T any](f func() (T, error)) PromiseWithError[T] {
 		// Make sure channel is always closed when asynchronous function completes.
 		defer close(ch)
 
-		// Make sure result message is always sent to a promise.
-		var msg executeChannelMessageType[T]
-		defer func() { ch <- msg }()
-
-		// Make sure panics are handles.
-		// Otherwise caller will receive nothing - neither result, nor error.
-		defer func() {
-			if panicArg := recover(); panicArg != nil {
-				if err, ok := panicArg.(error); ok {
-					msg.err = fmt.Errorf("asynchronous function panicked: %w", err)
-				} else {
-					msg.err = fmt.Errorf("asynchronous function panicked: %v", panicArg)
-				}
-			}
-		}()
+		// Result message is always sent to a promise, since call() handles panics.
+		ch <- call(task, policy, f)
+	}()
 
-		// Execute function f and store the result and error.
-		res, err := f()
+	return ch
+}
 
-		msg.res = res
-		msg.err = err
+// call function f synchronously.
+// Task is the name of the function to be reported if it panics.
+// Panics are handled according to the policy.
+func call[T any](task string, policy PanicPolicy, f func() (T, error)) (msg executeChannelMessageType[T]) {
+	// Make sure panics are handles.
+	// Otherwise caller will receive nothing - neither result, nor error.
+	defer func() {
+		if panicArg := recover(); panicArg != nil {
+			msg.err = policy.handle(panicArg, task)
+			msg.repanic = policy.Mode == PanicOnAwait
+		}
 	}()
 
+	// Execute function f and store the result and error.
+	msg.res, msg.err = f()
+
+	return msg
+}
+
+// newPromise creates promise which receives result from channel ch.
+// Channel must be closed after result is sent.
+func newPromise[T any](ch <-chan executeChannelMessageType[T]) PromiseWithError[T] {
 	return func() (T, error) {
 		msg, ok := <-ch
 		if !ok {
 			msg.err = ErrPromiseAlreadyExecuted
 		}
 
-		return msg.res, msg.err
+		return msg.result()
+	}
+}
+
+// result returns function's result, raising panic again if panic policy requires so.
+func (msg executeChannelMessageType[T]) result() (T, error) {
+	if msg.repanic {
+		panic(msg.err)
 	}
+	return msg.res, msg.err
 }
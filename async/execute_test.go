@@ -9,6 +9,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"dexm.lol/async"
 )
@@ -179,7 +180,7 @@ func TestExecute_doesNotLeakGoroutines(t *testing.T) {
 	length := runtime.Stack(buff, true)
 	stackTrace := string(buff[:length])
 
-	if strings.Contains(stackTrace, "dexm.lol/async.Execute[...].") {
+	if strings.Contains(stackTrace, "dexm.lol/async.execute[...].") {
 		t.Errorf("async.Execute function still has goroutine active:\n%s\n", stackTrace)
 	}
 }
@@ -208,3 +209,172 @@ func TestExecute_callingPromiseRepeatedlyReturnsError(t *testing.T) {
 		t.Errorf("Unexpected error received from the promise: %#v", err)
 	}
 }
+
+func ExampleExecuteContext() {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (string, error) {
+		// Perform some lengthy operation, which respects context.
+		select {
+		case <-time.After(time.Hour):
+			return "string result of some lengthy operation", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+
+	// Abort lengthy operation.
+	cancel()
+
+	res, err := promise(context.TODO())
+	fmt.Printf("Result: %q\n", res)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Result: ""
+	// Error: context canceled
+}
+
+func TestExecuteContext_passesResultToPromise(t *testing.T) {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (string, error) {
+		return "dummy result", nil
+	})
+	defer cancel()
+
+	res, err := promise(context.TODO())
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_passesErrorToPromise(t *testing.T) {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (interface{}, error) {
+		return nil, dummyError
+	})
+	defer cancel()
+
+	res, err := promise(context.TODO())
+	if res != nil {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_passesDerivedContext(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.TODO(), contextKey{}, "dummy value")
+
+	var derivedCtx context.Context
+	promise, cancel := async.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		derivedCtx = ctx
+		return ctx.Value(contextKey{}), ctx.Err()
+	})
+	defer cancel()
+
+	res, err := promise(context.TODO())
+	if res != "dummy value" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	// Derived context is released when function completes.
+	if derivedCtx.Err() == nil {
+		t.Error("Expected derived context to be cancelled after function has completed")
+	}
+}
+
+func TestExecuteContext_cancelAbortsFunction(t *testing.T) {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	cancel()
+
+	_, err := promise(context.TODO())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_parentContextAbortsFunction(t *testing.T) {
+	ctx, cancelParent := context.WithCancel(context.TODO())
+
+	promise, cancel := async.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	defer cancel()
+
+	cancelParent()
+
+	_, err := promise(context.TODO())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_promiseRespectsCallerContext(t *testing.T) {
+	chRelease := make(chan struct{})
+
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (string, error) {
+		<-chRelease
+		return "dummy result", nil
+	})
+	defer cancel()
+
+	ctx, cancelWait := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancelWait()
+
+	res, err := promise(ctx)
+	if res != "" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	// Promise can be called again after caller has given up.
+	close(chRelease)
+
+	res, err = promise(context.TODO())
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_handlesPanics(t *testing.T) {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (interface{}, error) {
+		panic(dummyError)
+	})
+	defer cancel()
+
+	_, err := promise(context.TODO())
+	if !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestExecuteContext_callingPromiseRepeatedlyReturnsError(t *testing.T) {
+	promise, cancel := async.ExecuteContext(context.TODO(), func(ctx context.Context) (string, error) {
+		return "dummy result", nil
+	})
+	defer cancel()
+
+	if _, err := promise(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	_, err := promise(context.TODO())
+	if !errors.Is(err, async.ErrPromiseAlreadyExecuted) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
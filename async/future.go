@@ -0,0 +1,99 @@
+package async
+
+import (
+	"context"
+)
+
+// Future holds result of an asynchronous execution.
+// Unlike promise, future can be awaited any number of times from any goroutine, result is memoized.
+type Future[T any] struct {
+	done chan struct{}
+	msg  executeChannelMessageType[T]
+}
+
+// ExecuteFuture executes function f asynchronously.
+// Returns future which can be awaited any number of times to retrieve function's f result.
+func ExecuteFuture[T any](f func() (T, error)) *Future[T] {
+	return executeFuture(taskName(f), f)
+}
+
+// ExecuteFutureContext executes function f asynchronously with a context derived from ctx.
+// Returns future which can be awaited any number of times to retrieve function's f result and a function which cancels derived context.
+// Derived context is cancelled automatically when function f returns.
+func ExecuteFutureContext[T any](ctx context.Context, f func(context.Context) (T, error)) (*Future[T], context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	future := executeFuture(taskName(f), func() (T, error) {
+		// Release context resources as soon as function f completes.
+		defer cancel()
+
+		return f(ctx)
+	})
+
+	return future, cancel
+}
+
+// executeFuture executes function f asynchronously.
+// Task is the name of the function to be reported if it panics.
+func executeFuture[T any](task string, f func() (T, error)) *Future[T] {
+	future := newFuture[T]()
+
+	ch := execute(task, f)
+	go func() {
+		future.msg = <-ch
+		close(future.done)
+	}()
+
+	return future
+}
+
+// newFuture creates future which is not resolved yet.
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{
+		done: make(chan struct{}),
+	}
+}
+
+// Await blocks until result is available and returns it.
+func (f *Future[T]) Await() (T, error) {
+	<-f.done
+	return f.msg.result()
+}
+
+// AwaitContext blocks until result is available or until context is done.
+// If context is done first, returns context's error.
+func (f *Future[T]) AwaitContext(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.msg.result()
+	case <-ctx.Done():
+		var res T
+		return res, ctx.Err()
+	}
+}
+
+// Done returns a channel which is closed when result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// IsDone reports whether result is available.
+func (f *Future[T]) IsDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// TryGet returns result without blocking.
+// Reports false if result is not available yet.
+func (f *Future[T]) TryGet() (T, bool, error) {
+	if !f.IsDone() {
+		var res T
+		return res, false, nil
+	}
+	res, err := f.msg.result()
+	return res, true, err
+}
@@ -0,0 +1,143 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleExecuteFuture() {
+	future := async.ExecuteFuture(func() (string, error) {
+		// Perform some lengthy operation.
+
+		return "string result of some lengthy operation", nil
+	})
+
+	// Wait for the result in select statement.
+	select {
+	case <-future.Done():
+	case <-time.After(time.Second):
+		return
+	}
+
+	// Future can be awaited repeatedly.
+	for i := 0; i < 2; i++ {
+		res, err := future.Await()
+		fmt.Println("Result:", res)
+		fmt.Println("Error:", err)
+	}
+
+	// Output:
+	// Result: string result of some lengthy operation
+	// Error: <nil>
+	// Result: string result of some lengthy operation
+	// Error: <nil>
+}
+
+func TestFuture_awaitedFromMultipleGoroutines(t *testing.T) {
+	var calls int
+	future := async.ExecuteFuture(func() (string, error) {
+		calls++
+		return "dummy result", dummyError
+	})
+
+	var wg sync.WaitGroup
+	wg.Add(10)
+
+	for i := 0; i < 10; i++ {
+		go func() {
+			defer wg.Done()
+
+			res, err := future.Await()
+			if res != "dummy result" {
+				t.Errorf("Unexpected result received from the future: %#v", res)
+			}
+			if !errors.Is(err, dummyError) {
+				t.Errorf("Unexpected error received from the future: %#v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Function was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestFuture_TryGet(t *testing.T) {
+	chRelease := make(chan struct{})
+
+	future := async.ExecuteFuture(func() (string, error) {
+		<-chRelease
+		return "dummy result", nil
+	})
+
+	if future.IsDone() {
+		t.Error("Future reported to be done before function has completed")
+	}
+	if res, ok, err := future.TryGet(); ok || res != "" || err != nil {
+		t.Errorf("Unexpected result received from the future: %#v, %#v, %#v", res, ok, err)
+	}
+
+	close(chRelease)
+	<-future.Done()
+
+	if !future.IsDone() {
+		t.Error("Future reported not to be done after function has completed")
+	}
+	if res, ok, err := future.TryGet(); !ok || res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result received from the future: %#v, %#v, %#v", res, ok, err)
+	}
+}
+
+func TestFuture_AwaitContext(t *testing.T) {
+	chRelease := make(chan struct{})
+	defer close(chRelease)
+
+	future := async.ExecuteFuture(func() (string, error) {
+		<-chRelease
+		return "dummy result", nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	res, err := future.AwaitContext(ctx)
+	if res != "" {
+		t.Errorf("Unexpected result received from the future: %#v", res)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error received from the future: %#v", err)
+	}
+}
+
+func TestFuture_handlesPanics(t *testing.T) {
+	future := async.ExecuteFuture(func() (interface{}, error) {
+		panic(dummyError)
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := future.Await(); !errors.Is(err, dummyError) {
+			t.Errorf("Unexpected error received from the future: %#v", err)
+		}
+	}
+}
+
+func TestExecuteFutureContext_cancelAbortsFunction(t *testing.T) {
+	future, cancel := async.ExecuteFutureContext(context.TODO(), func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	cancel()
+
+	if _, err := future.Await(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error received from the future: %#v", err)
+	}
+}
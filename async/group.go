@@ -1,7 +1,7 @@
 package async
 
 import (
-	"fmt"
+	"context"
 	"sync"
 )
 
@@ -11,10 +11,103 @@ import (
 type Group struct {
 	funcs    []func(*sync.WaitGroup, chan<- error)
 	executed bool
+	limit    int
+
+	// Panic policy overriding package level policy, see SetPanicPolicy().
+	panicPolicy *PanicPolicy
+
+	// Rate limiter, see SetRateLimit().
+	limiter RateLimiter
+
+	// Semaphore, see SetSemaphore().
+	semaphore *Semaphore
+
+	// Fail-fast mode, see GroupWithContext().
+	failFast bool
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// GroupWithContext creates a fail-fast group and a context derived from ctx.
+// Functions added to the group should use returned context to abort their execution.
+//
+// Context is cancelled when the first function fails or when all functions have executed, whichever occurs first.
+// Method Execute() of the fail-fast group returns as soon as the first function fails with only that error,
+// without waiting for the remaining functions.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{failFast: true, ctx: ctx, cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of functions executing at the same time to n.
+// Remaining functions are queued and launched in the order they were added to the group.
+// Zero or negative n removes the limit.
+// Queued functions of a fail-fast group are still launched after the first error, they should check group's context.
+//
+// SetLimit must be called before Execute().
+func (g *Group) SetLimit(n int) {
+	g.limit = n
+}
+
+// SetPanicPolicy sets panic policy for functions of this group, overriding package level policy.
+//
+// SetPanicPolicy must be called before Execute().
+func (g *Group) SetPanicPolicy(policy PanicPolicy) {
+	g.panicPolicy = &policy
+}
+
+// SetRateLimit limits the rate at which functions are launched.
+// Each function waits for the limiter before being executed.
+// If waiting fails (e.g. context of fail-fast group is cancelled), function is not executed and limiter's error is returned instead.
+//
+// SetRateLimit must be called before Execute().
+func (g *Group) SetRateLimit(limiter RateLimiter) {
+	g.limiter = limiter
+}
+
+// SetSemaphore limits the total weight of functions executing at the same time.
+// Each function acquires semaphore with its weight before being executed and releases it afterwards.
+// Functions added by AddToExecutionGroup() have weight 1, see AddWeightedToExecutionGroup() to specify the weight.
+// If acquiring fails (e.g. context of fail-fast group is cancelled), function is not executed and semaphore's error is returned instead.
+//
+// SetSemaphore must be called before Execute().
+func (g *Group) SetSemaphore(semaphore *Semaphore) {
+	g.semaphore = semaphore
+}
+
+// acquire waits for the group's rate limiter and acquires group's semaphore with the weight if they are set.
+// Returns function which releases the semaphore.
+func (g *Group) acquire(weight int) (func(), error) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if err := waitRateLimit(ctx, g.limiter); err != nil {
+		return nil, err
+	}
+
+	if g.semaphore == nil {
+		return func() {}, nil
+	}
+	if err := g.semaphore.Acquire(ctx, weight); err != nil {
+		return nil, err
+	}
+	return func() { g.semaphore.Release(weight) }, nil
+}
+
+// effectivePanicPolicy returns panic policy of this group or package level policy if group's policy is not set.
+func (g *Group) effectivePanicPolicy() PanicPolicy {
+	if g.panicPolicy != nil {
+		return *g.panicPolicy
+	}
+	return currentPanicPolicy()
 }
 
 // Execute functions added to the group.
 // Will block until all functions have executed and will return aggregated errors.
+// Fail-fast group will return early on the first error (see GroupWithContext()).
+// If panic policy mode is PanicOnAwait, Execute panics with the first encountered PanicError.
 //
 // Execute can be called only once.
 // Calling Execute() repeatedly will result in an error.
@@ -42,13 +135,49 @@ func (g *Group) Execute() (errs AggregatedError) {
 	}()
 
 	// Launch all functions.
-	for _, f := range g.funcs {
-		go f(&wg, errCh)
+	if g.limit <= 0 || g.limit >= len(g.funcs) {
+		for _, f := range g.funcs {
+			go f(&wg, errCh)
+		}
+	} else {
+		// Queue all functions in the order they were added.
+		funcCh := make(chan func(*sync.WaitGroup, chan<- error), len(g.funcs))
+		for _, f := range g.funcs {
+			funcCh <- f
+		}
+		close(funcCh)
+
+		// Launch limited number of workers, which will execute queued functions one by one.
+		for i := 0; i < g.limit; i++ {
+			go func() {
+				for f := range funcCh {
+					f(&wg, errCh)
+				}
+			}()
+		}
 	}
 
 	// Collect and return errors.
 	for err := range errCh {
 		errs = append(errs, err)
+
+		// Do not wait for the remaining functions in fail-fast mode.
+		if g.failFast {
+			break
+		}
+	}
+
+	// Signal the remaining functions to abort and release context resources.
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	// Raise panic again if panic policy requires so.
+	if g.effectivePanicPolicy().Mode == PanicOnAwait {
+		var panicErr *PanicError
+		if errs.Find(&panicErr) {
+			panic(panicErr)
+		}
 	}
 
 	return
@@ -59,11 +188,23 @@ func (g *Group) Execute() (errs AggregatedError) {
 //
 // Promise can be called only once.
 // Calling promise repeatedly will result in panic.
+// Promise panics with PanicError if function f panicked and panic policy mode is PanicOnAwait.
 func AddToExecutionGroup[T any](group *Group, f func() (T, error)) Promise[T] {
+	return AddWeightedToExecutionGroup(group, 1, f)
+}
+
+// AddWeightedToExecutionGroup registers function f with the execution group, same as AddToExecutionGroup().
+// Function acquires group's semaphore with the weight before being executed, see Group.SetSemaphore().
+func AddWeightedToExecutionGroup[T any](group *Group, weight int, f func() (T, error)) Promise[T] {
 	// This channel is buffered. It will be written to only once.
 	// That way when function f completes, goroutine will end as well (even if promise is never called and channel not drained).
 	resCh := make(chan T, 1)
 
+	task := taskName(f)
+
+	// Panic, which must be raised again when promise is called.
+	var repanicErr *PanicError
+
 	group.funcs = append(group.funcs, func(wg *sync.WaitGroup, errCh chan<- error) {
 		// Make sure wait group is notified about completion of this function.
 		defer wg.Done()
@@ -85,16 +226,25 @@ func AddToExecutionGroup[T any](group *Group, f func() (T, error)) Promise[T] {
 
 		// Make sure panics are handles.
 		// Otherwise caller will receive nothing - neither result, nor error.
+		policy := group.effectivePanicPolicy()
 		defer func() {
 			if panicArg := recover(); panicArg != nil {
-				if err, ok := panicArg.(error); ok {
-					resErr = fmt.Errorf("asynchronous function panicked: %w", err)
-				} else {
-					resErr = fmt.Errorf("asynchronous function panicked: %v", panicArg)
+				panicErr := policy.handle(panicArg, task)
+				if policy.Mode == PanicOnAwait {
+					repanicErr = panicErr
 				}
+				resErr = panicErr
 			}
 		}()
 
+		// Make sure rate limit and semaphore are respected.
+		release, err := group.acquire(weight)
+		if err != nil {
+			resErr = err
+			return
+		}
+		defer release()
+
 		// Execute function f and store the result and error.
 		resData, resErr = f()
 	})
@@ -104,6 +254,9 @@ func AddToExecutionGroup[T any](group *Group, f func() (T, error)) Promise[T] {
 		if !ok {
 			panic(ErrPromiseAlreadyExecuted)
 		}
+		if repanicErr != nil {
+			panic(repanicErr)
+		}
 		return msg
 	}
 }
@@ -1,10 +1,13 @@
 package async_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"dexm.lol/async"
 )
@@ -229,3 +232,261 @@ func TestGroup_handlesPanicsAndWrapsOriginalError(t *testing.T) {
 		t.Errorf("Unexpected result received from the promise 2: %#v", res)
 	}
 }
+
+func ExampleGroupWithContext() {
+	group, ctx := async.GroupWithContext(context.TODO())
+
+	promise1 := async.AddToExecutionGroup(group, func() (string, error) {
+		// Perform some lengthy operation, which fails.
+
+		return "", errors.New("lengthy operation failed")
+	})
+
+	promise2 := async.AddToExecutionGroup(group, func() (string, error) {
+		// Perform another lengthy operation, which respects context.
+		select {
+		case <-time.After(time.Hour):
+			return "string result of another lengthy operation", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	})
+
+	if err := group.Execute(); err != nil {
+		for _, err := range err {
+			fmt.Println("Error:", err)
+		}
+	}
+
+	fmt.Printf("Promise 1 result: %q\n", promise1())
+	fmt.Printf("Promise 2 result: %q\n", promise2())
+
+	// Output:
+	// Error: lengthy operation failed
+	// Promise 1 result: ""
+	// Promise 2 result: ""
+}
+
+func TestGroupWithContext_returnsFirstErrorWithoutWaiting(t *testing.T) {
+	chRelease := make(chan struct{})
+	defer close(chRelease)
+
+	group, _ := async.GroupWithContext(context.TODO())
+
+	async.AddToExecutionGroup(group, func() (interface{}, error) {
+		<-chRelease
+		return nil, dummyError1
+	})
+
+	async.AddToExecutionGroup(group, func() (interface{}, error) {
+		return nil, dummyError2
+	})
+
+	err := group.Execute()
+	if len(err) != 1 {
+		t.Fatalf("Expected aggregated error to have 1 error, but got: %#v", err)
+	}
+	if !err.Has(dummyError2) {
+		t.Errorf("Expected aggregated error to contain 2nd error: %#v", err)
+	}
+}
+
+func TestGroupWithContext_cancelsContextOnFirstError(t *testing.T) {
+	group, ctx := async.GroupWithContext(context.TODO())
+
+	promise := async.AddToExecutionGroup(group, func() (error, error) {
+		<-ctx.Done()
+		return ctx.Err(), nil
+	})
+
+	async.AddToExecutionGroup(group, func() (interface{}, error) {
+		return nil, dummyError
+	})
+
+	if err := group.Execute(); !err.Has(dummyError) {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+
+	if err := promise(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected result received from the promise: %#v", err)
+	}
+}
+
+func TestGroupWithContext_cancelsContextAfterSuccessfulExecution(t *testing.T) {
+	group, ctx := async.GroupWithContext(context.TODO())
+
+	promise := async.AddToExecutionGroup(group, func() (string, error) {
+		return "dummy result", ctx.Err()
+	})
+
+	if err := group.Execute(); err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+
+	if res := promise(); res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+
+	if ctx.Err() == nil {
+		t.Error("Expected context to be cancelled after execution")
+	}
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	const limit = 2
+
+	var group async.Group
+	group.SetLimit(limit)
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	var order []int
+
+	for i := 0; i < 6; i++ {
+		i := i
+		async.AddToExecutionGroup(&group, func() (interface{}, error) {
+			mu.Lock()
+			order = append(order, i)
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			return nil, fmt.Errorf("error %d: %w", i, dummyError)
+		})
+	}
+
+	err := group.Execute()
+	if len(err) != 6 {
+		t.Fatalf("Expected aggregated error to have 6 errors, but got: %#v", err)
+	}
+
+	if maxRunning != limit {
+		t.Errorf("Unexpected number of functions running at the same time: %d", maxRunning)
+	}
+
+	// Functions are started in the order they were added, but workers may race to record the start.
+	for i, idx := range order {
+		if idx < i-limit+1 || idx > i+limit-1 {
+			t.Errorf("Functions were not started in the order they were added: %v", order)
+			break
+		}
+	}
+}
+
+func TestGroup_SetLimitPassesResultsToPromises(t *testing.T) {
+	var group async.Group
+	group.SetLimit(1)
+
+	promise1 := async.AddToExecutionGroup(&group, func() (string, error) {
+		return "dummy result 1", nil
+	})
+
+	promise2 := async.AddToExecutionGroup(&group, func() (string, error) {
+		return "dummy result 2", nil
+	})
+
+	if err := group.Execute(); err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+
+	if res := promise1(); res != "dummy result 1" {
+		t.Errorf("Unexpected result received from the promise 1: %#v", res)
+	}
+
+	if res := promise2(); res != "dummy result 2" {
+		t.Errorf("Unexpected result received from the promise 2: %#v", res)
+	}
+}
+
+func TestGroup_SetRateLimit(t *testing.T) {
+	clock := newFakeClock()
+
+	var group async.Group
+	group.SetRateLimit(async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Second,
+		Burst:    1,
+		Clock:    clock,
+	}))
+
+	for i := 0; i < 3; i++ {
+		async.AddToExecutionGroup(&group, func() (interface{}, error) {
+			return nil, nil
+		})
+	}
+
+	if err := group.Execute(); err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+	if delays := clock.Delays(); len(delays) != 2 {
+		t.Errorf("Unexpected delays of rate limiter: %v", delays)
+	}
+}
+
+func TestGroupWithContext_SetRateLimitStopsWaitingOnFirstError(t *testing.T) {
+	group, _ := async.GroupWithContext(context.TODO())
+	group.SetLimit(1)
+	group.SetRateLimit(async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Hour,
+		Burst:    1,
+	}))
+
+	async.AddToExecutionGroup(group, func() (interface{}, error) {
+		return nil, dummyError
+	})
+	promise := async.AddToExecutionGroup(group, func() (string, error) {
+		t.Error("Function was executed despite rate limit")
+		return "dummy result", nil
+	})
+
+	if err := group.Execute(); !err.Has(dummyError) {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+
+	// Second function stops waiting for the rate limiter once the context is cancelled.
+	if res := promise(); res != "" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+}
+
+func TestGroup_SetSemaphore(t *testing.T) {
+	var group async.Group
+	group.SetSemaphore(async.NewSemaphore(10))
+
+	var mu sync.Mutex
+	var weight, maxWeight int
+
+	for _, w := range []int{6, 6, 4, 3} {
+		w := w
+		async.AddWeightedToExecutionGroup(&group, w, func() (interface{}, error) {
+			mu.Lock()
+			weight += w
+			if weight > maxWeight {
+				maxWeight = weight
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			weight -= w
+			mu.Unlock()
+
+			return nil, nil
+		})
+	}
+
+	if err := group.Execute(); err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+	if maxWeight > 10 {
+		t.Errorf("Semaphore size was exceeded: %d", maxWeight)
+	}
+}
@@ -0,0 +1,98 @@
+package async
+
+import (
+	"context"
+	"time"
+)
+
+// HedgePolicy defines when Hedge() launches additional attempts.
+type HedgePolicy struct {
+	// Delays before launching each additional attempt, measured from the launch of the previous attempt.
+	// Number of delays is the maximum number of additional attempts.
+	Delays []time.Duration
+
+	// Clock to wait between attempts, SystemClock is used if nil.
+	Clock Clock
+}
+
+// Hedge executes function f asynchronously and launches additional attempts of f
+// if previous attempts have not succeeded within delays defined by the policy.
+// If an attempt fails, the next one is launched immediately without waiting for the delay.
+// Returns promise which will return result of the first successful attempt.
+//
+// All attempts share a context derived from ctx, which is cancelled once the first attempt succeeds,
+// so the remaining attempts should use it to abort their execution.
+//
+// If all attempts fail, promise returns AggregatedError with errors of all attempts in the order they have failed.
+// If context is done before any attempt succeeds, context's error is added to aggregated error as well,
+// without waiting for the remaining attempts.
+func Hedge[T any](ctx context.Context, policy HedgePolicy, f func(context.Context) (T, error)) PromiseWithError[T] {
+	return Execute(func() (T, error) {
+		return hedge(ctx, policy, f)
+	})
+}
+
+// hedge launches attempts of function f according to the policy, see Hedge().
+func hedge[T any](ctx context.Context, policy HedgePolicy, f func(context.Context) (T, error)) (res T, err error) {
+	clock := clockOrDefault(policy.Clock)
+
+	// Make sure remaining attempts are cancelled when the result is known.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Channel is buffered to fit results of all attempts.
+	// That way attempts, which complete after hedge returns, do not block.
+	ch := make(chan executeChannelMessageType[T], len(policy.Delays)+1)
+
+	task := taskName(f)
+	panicPolicy := currentPanicPolicy()
+
+	// Timer of the next attempt, nil when there are no more attempts.
+	var timer <-chan time.Time
+	launched, running := 0, 0
+
+	launch := func() {
+		go func() {
+			ch <- call(task, panicPolicy, func() (T, error) { return f(ctx) })
+		}()
+
+		launched++
+		running++
+
+		timer = nil
+		if launched <= len(policy.Delays) {
+			timer = clock.After(policy.Delays[launched-1])
+		}
+	}
+
+	launch()
+
+	var errs AggregatedError
+	for running > 0 {
+		// Check context first, select does not prioritize between ready cases.
+		if ctx.Err() != nil {
+			return res, append(errs, ctx.Err())
+		}
+
+		select {
+		case msg := <-ch:
+			running--
+
+			if msg.err == nil || msg.repanic {
+				return msg.result()
+			}
+			errs = append(errs, msg.err)
+
+			// Do not wait for the delay, since the current attempt has already failed.
+			if timer != nil {
+				launch()
+			}
+		case <-timer:
+			launch()
+		case <-ctx.Done():
+			return res, append(errs, ctx.Err())
+		}
+	}
+
+	return res, errs
+}
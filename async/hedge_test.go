@@ -0,0 +1,156 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleHedge() {
+	var attempts int32
+
+	promise := async.Hedge(context.TODO(), async.HedgePolicy{
+		Delays: []time.Duration{10 * time.Millisecond},
+	}, func(ctx context.Context) (string, error) {
+		// The first request is stuck, the backup request answers quickly.
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+
+		return "string result of backup request", nil
+	})
+
+	res, err := promise()
+	fmt.Println("Result:", res)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Result: string result of backup request
+	// Error: <nil>
+}
+
+func TestHedge_cancelsRemainingAttempts(t *testing.T) {
+	var attempts int32
+	cancelled := make(chan struct{})
+
+	res, err := async.Hedge(context.TODO(), async.HedgePolicy{
+		Delays: []time.Duration{time.Millisecond},
+	}, func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-ctx.Done()
+			close(cancelled)
+			return "", ctx.Err()
+		}
+		return "dummy result", nil
+	})()
+
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Error("Slow attempt was not cancelled")
+	}
+}
+
+func TestHedge_doesNotHedgeFastAttempts(t *testing.T) {
+	var attempts int32
+
+	res, err := async.Hedge(context.TODO(), async.HedgePolicy{
+		Delays: []time.Duration{time.Hour, time.Hour},
+	}, func(ctx context.Context) (string, error) {
+		atomic.AddInt32(&attempts, 1)
+		return "dummy result", nil
+	})()
+
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+	if attempts := atomic.LoadInt32(&attempts); attempts != 1 {
+		t.Errorf("Unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestHedge_launchesNextAttemptOnFailure(t *testing.T) {
+	res, err := async.Hedge(context.TODO(), async.HedgePolicy{
+		Delays: []time.Duration{time.Hour, time.Hour},
+	}, failingFunc(dummyError1, dummyError2))()
+
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestHedge_allAttemptsFail(t *testing.T) {
+	clock := newFakeClock()
+	delays := []time.Duration{time.Second, 2 * time.Second}
+
+	_, err := async.Hedge(context.TODO(), async.HedgePolicy{
+		Delays: delays,
+		Clock:  clock,
+	}, func(ctx context.Context) (string, error) {
+		return "", dummyError
+	})()
+
+	var aggregatedErr async.AggregatedError
+	if !errors.As(err, &aggregatedErr) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if len(aggregatedErr) != 3 || !aggregatedErr.Has(dummyError) {
+		t.Errorf("Unexpected errors of attempts: %#v", aggregatedErr)
+	}
+	if launched := clock.Delays(); !reflect.DeepEqual(launched, delays) {
+		t.Errorf("Unexpected delays between attempts: %v", launched)
+	}
+}
+
+func TestHedge_contextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	promise := async.Hedge(ctx, async.HedgePolicy{
+		Delays: []time.Duration{time.Hour},
+	}, func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", dummyError
+	})
+	cancel()
+
+	_, err := promise()
+	if errs, _ := err.(async.AggregatedError); !errs.Has(context.Canceled) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestHedge_panickingAttempt(t *testing.T) {
+	_, err := async.Hedge(context.TODO(), async.HedgePolicy{}, func(ctx context.Context) (string, error) {
+		panic(dummyError)
+	})()
+
+	errs, _ := err.(async.AggregatedError)
+
+	var panicErr *async.PanicError
+	if !errs.Find(&panicErr) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !errs.Has(dummyError) {
+		t.Errorf("Panic error does not wrap the original error: %#v", err)
+	}
+}
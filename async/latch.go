@@ -0,0 +1,76 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// CountDownLatch allows goroutines to wait until a set of operations completes.
+// Latch is released once CountDown() is called count times, it can not be reset.
+type CountDownLatch struct {
+	mu    sync.Mutex
+	count int
+	done  chan struct{}
+}
+
+// NewCountDownLatch creates latch, which is released after count calls of CountDown().
+// Latch with zero count is released immediately.
+// Panics with ErrInvalidLatchCount if count is negative.
+func NewCountDownLatch(count int) *CountDownLatch {
+	if count < 0 {
+		panic(ErrInvalidLatchCount)
+	}
+
+	l := &CountDownLatch{count: count, done: make(chan struct{})}
+	if count == 0 {
+		close(l.done)
+	}
+	return l
+}
+
+// CountDown decrements the count, releasing the latch when count reaches zero.
+// Calling CountDown() on a released latch has no effect.
+func (l *CountDownLatch) CountDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return
+	}
+
+	l.count--
+	if l.count == 0 {
+		close(l.done)
+	}
+}
+
+// Count returns the number of CountDown() calls remaining until the latch is released.
+func (l *CountDownLatch) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.count
+}
+
+// Done returns channel, which is closed when the latch is released.
+func (l *CountDownLatch) Done() <-chan struct{} {
+	return l.done
+}
+
+// Wait blocks until the latch is released or context is done.
+// Returns context's error if context is done first.
+func (l *CountDownLatch) Wait(ctx context.Context) error {
+	// Check latch first, select does not prioritize between ready cases.
+	select {
+	case <-l.done:
+		return nil
+	default:
+	}
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
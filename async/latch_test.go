@@ -0,0 +1,83 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleCountDownLatch() {
+	latch := async.NewCountDownLatch(3)
+
+	for i := 0; i < 3; i++ {
+		go func() {
+			// Initialize some service.
+
+			latch.CountDown()
+		}()
+	}
+
+	if err := latch.Wait(context.TODO()); err != nil {
+		fmt.Println("Error:", err)
+		return
+	}
+
+	fmt.Println("All services are initialized")
+
+	// Output:
+	// All services are initialized
+}
+
+func TestCountDownLatch_Done(t *testing.T) {
+	latch := async.NewCountDownLatch(2)
+
+	latch.CountDown()
+	select {
+	case <-latch.Done():
+		t.Fatal("Latch was released too early")
+	default:
+	}
+	if count := latch.Count(); count != 1 {
+		t.Errorf("Unexpected count of the latch: %d", count)
+	}
+
+	latch.CountDown()
+	latch.CountDown()
+	select {
+	case <-latch.Done():
+	default:
+		t.Fatal("Latch was not released")
+	}
+	if count := latch.Count(); count != 0 {
+		t.Errorf("Unexpected count of the latch: %d", count)
+	}
+}
+
+func TestCountDownLatch_zeroCount(t *testing.T) {
+	if err := async.NewCountDownLatch(0).Wait(context.TODO()); err != nil {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+}
+
+func TestCountDownLatch_WaitRespectsContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	if err := async.NewCountDownLatch(1).Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+}
+
+func TestNewCountDownLatch_panicsOnInvalidCount(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidLatchCount {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewCountDownLatch(-1)
+}
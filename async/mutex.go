@@ -0,0 +1,223 @@
+package async
+
+import (
+	"context"
+	"sync"
+)
+
+// Maximum number of readers holding RWMutex at the same time.
+const rwMutexMaxReaders = 1 << 30
+
+// Mutex is a mutual exclusion lock, which can be awaited with a context.
+// Waiters acquire the lock in FIFO order.
+// Zero value is an unlocked mutex.
+type Mutex struct {
+	once      sync.Once
+	semaphore *Semaphore
+}
+
+// RWMutex is a reader/writer mutual exclusion lock, which can be awaited with a context.
+// Waiters acquire the lock in FIFO order, so a waiting writer blocks new readers and is not starved by them.
+// Zero value is an unlocked mutex.
+type RWMutex struct {
+	once      sync.Once
+	semaphore *Semaphore
+
+	// Mode in which the mutex is locked, validates unlocking.
+	// Lock is not held while calling semaphore, since semaphore updates the mode while holding its own lock.
+	mu      sync.Mutex
+	writer  bool
+	readers int
+}
+
+// Ensure interface implementation
+var (
+	_ sync.Locker = &Mutex{}
+	_ sync.Locker = &RWMutex{}
+)
+
+// LockContext blocks until the mutex is locked or context is done.
+// Returns context's error if context is done first, mutex is not locked in this case.
+func (m *Mutex) LockContext(ctx context.Context) error {
+	return m.init().Acquire(ctx, 1)
+}
+
+// Lock blocks until the mutex is locked.
+func (m *Mutex) Lock() {
+	_ = m.LockContext(context.Background())
+}
+
+// LockChan starts locking the mutex, allows to wait for the mutex in select along with other channels.
+// Returns channel which is closed once the mutex is locked and function which cancels locking.
+// Cancel function must be called if caller stops waiting for the channel, it unlocks the mutex if it was already locked.
+// Cancel function must not be called after receiving from the channel.
+func (m *Mutex) LockChan() (<-chan struct{}, func()) {
+	ready, stop := m.init().acquireChan(1, nil)
+	return ready, cancelLock(stop, m.Unlock)
+}
+
+// TryLock locks the mutex if it is unlocked and there are no waiters.
+// Reports whether mutex was locked.
+func (m *Mutex) TryLock() bool {
+	return m.init().TryAcquire(1)
+}
+
+// Unlock the mutex.
+// Panics with ErrMutexNotLocked if mutex is not locked.
+func (m *Mutex) Unlock() {
+	if !m.init().release(1) {
+		panic(ErrMutexNotLocked)
+	}
+}
+
+// init creates semaphore of the mutex on first use.
+func (m *Mutex) init() *Semaphore {
+	m.once.Do(func() {
+		m.semaphore = NewSemaphore(1)
+	})
+	return m.semaphore
+}
+
+// LockContext blocks until the mutex is locked for writing or context is done.
+// Returns context's error if context is done first, mutex is not locked in this case.
+func (m *RWMutex) LockContext(ctx context.Context) error {
+	if err := m.init().Acquire(ctx, rwMutexMaxReaders); err != nil {
+		return err
+	}
+
+	m.locked(true)
+	return nil
+}
+
+// Lock blocks until the mutex is locked for writing.
+func (m *RWMutex) Lock() {
+	_ = m.LockContext(context.Background())
+}
+
+// LockChan starts locking the mutex for writing, see Mutex.LockChan().
+func (m *RWMutex) LockChan() (<-chan struct{}, func()) {
+	ready, stop := m.init().acquireChan(rwMutexMaxReaders, func() { m.locked(true) })
+	return ready, cancelLock(stop, m.Unlock)
+}
+
+// TryLock locks the mutex for writing if it is unlocked and there are no waiters.
+// Reports whether mutex was locked.
+func (m *RWMutex) TryLock() bool {
+	if !m.init().TryAcquire(rwMutexMaxReaders) {
+		return false
+	}
+
+	m.locked(true)
+	return true
+}
+
+// Unlock the mutex locked for writing.
+// Panics with ErrMutexNotLocked if mutex is not locked for writing.
+func (m *RWMutex) Unlock() {
+	if !m.unlocked(true) {
+		panic(ErrMutexNotLocked)
+	}
+	m.init().release(rwMutexMaxReaders)
+}
+
+// RLockContext blocks until the mutex is locked for reading or context is done.
+// Returns context's error if context is done first, mutex is not locked in this case.
+func (m *RWMutex) RLockContext(ctx context.Context) error {
+	if err := m.init().Acquire(ctx, 1); err != nil {
+		return err
+	}
+
+	m.locked(false)
+	return nil
+}
+
+// RLock blocks until the mutex is locked for reading.
+func (m *RWMutex) RLock() {
+	_ = m.RLockContext(context.Background())
+}
+
+// RLockChan starts locking the mutex for reading, see Mutex.LockChan().
+func (m *RWMutex) RLockChan() (<-chan struct{}, func()) {
+	ready, stop := m.init().acquireChan(1, func() { m.locked(false) })
+	return ready, cancelLock(stop, m.RUnlock)
+}
+
+// TryRLock locks the mutex for reading if it is not locked for writing and there are no waiters.
+// Reports whether mutex was locked.
+func (m *RWMutex) TryRLock() bool {
+	if !m.init().TryAcquire(1) {
+		return false
+	}
+
+	m.locked(false)
+	return true
+}
+
+// RUnlock the mutex locked for reading.
+// Panics with ErrMutexNotLocked if mutex is not locked for reading.
+func (m *RWMutex) RUnlock() {
+	if !m.unlocked(false) {
+		panic(ErrMutexNotLocked)
+	}
+	m.init().release(1)
+}
+
+// RLocker returns sync.Locker, which locks the mutex for reading.
+func (m *RWMutex) RLocker() sync.Locker {
+	return (*rlocker)(m)
+}
+
+// init creates semaphore of the mutex on first use.
+func (m *RWMutex) init() *Semaphore {
+	m.once.Do(func() {
+		m.semaphore = NewSemaphore(rwMutexMaxReaders)
+	})
+	return m.semaphore
+}
+
+// locked records that the mutex was locked for writing or reading.
+func (m *RWMutex) locked(write bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if write {
+		m.writer = true
+	} else {
+		m.readers++
+	}
+}
+
+// unlocked records that the mutex was unlocked for writing or reading.
+// Reports whether mutex was locked in this mode.
+func (m *RWMutex) unlocked(write bool) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case write && m.writer:
+		m.writer = false
+	case !write && m.readers > 0:
+		m.readers--
+	default:
+		return false
+	}
+	return true
+}
+
+type rlocker RWMutex
+
+func (r *rlocker) Lock()   { (*RWMutex)(r).RLock() }
+func (r *rlocker) Unlock() { (*RWMutex)(r).RUnlock() }
+
+// cancelLock returns function, which stops waiting for the lock or unlocks it if it was already locked.
+// Returned function does nothing when called again.
+func cancelLock(stop func() bool, unlock func()) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if !stop() {
+				unlock()
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleMutex() {
+	var mu async.Mutex
+	mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	// Mutex is held by someone else, give up after timeout.
+	if err := mu.LockContext(ctx); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Output:
+	// Error: context deadline exceeded
+}
+
+func TestMutex_excludesConcurrentAccess(t *testing.T) {
+	var mu async.Mutex
+	var wg sync.WaitGroup
+
+	counter := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := mu.LockContext(context.TODO()); err != nil {
+				t.Errorf("Unexpected error: %#v", err)
+				return
+			}
+			defer mu.Unlock()
+
+			counter++
+		}()
+	}
+
+	wg.Wait()
+	if counter != 10 {
+		t.Errorf("Unexpected counter value: %d", counter)
+	}
+}
+
+func TestMutex_TryLock(t *testing.T) {
+	var mu async.Mutex
+
+	if !mu.TryLock() {
+		t.Fatal("Unlocked mutex was not locked")
+	}
+	if mu.TryLock() {
+		t.Fatal("Locked mutex was locked again")
+	}
+
+	mu.Unlock()
+	if !mu.TryLock() {
+		t.Error("Unlocked mutex was not locked")
+	}
+}
+
+func TestMutex_UnlockPanicsIfNotLocked(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrMutexNotLocked {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	var mu async.Mutex
+	mu.Unlock()
+}
+
+func TestMutex_LockChan(t *testing.T) {
+	var mu async.Mutex
+	mu.Lock()
+
+	chLocked, _ := mu.LockChan()
+	select {
+	case <-chLocked:
+		t.Fatal("Locked mutex was locked again")
+	default:
+	}
+
+	mu.Unlock()
+	<-chLocked
+
+	if mu.TryLock() {
+		t.Error("Mutex was locked while held by the channel waiter")
+	}
+	mu.Unlock()
+}
+
+func TestMutex_LockChanCancelled(t *testing.T) {
+	var mu async.Mutex
+	mu.Lock()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	chLocked, cancelLock := mu.LockChan()
+	select {
+	case <-chLocked:
+		t.Fatal("Locked mutex was locked again")
+	case <-ctx.Done():
+		cancelLock()
+	}
+
+	// Cancelled waiter does not receive the lock.
+	mu.Unlock()
+	if !mu.TryLock() {
+		t.Fatal("Mutex was not locked after waiter has cancelled")
+	}
+
+	// Lock acquired after caller has stopped waiting is unlocked on cancellation.
+	_, cancelLock = mu.LockChan()
+	mu.Unlock()
+	cancelLock()
+	cancelLock()
+
+	if !mu.TryLock() {
+		t.Error("Mutex was not unlocked on cancellation")
+	}
+}
+
+func TestRWMutex_allowsConcurrentReaders(t *testing.T) {
+	var mu async.RWMutex
+
+	mu.RLock()
+	if !mu.TryRLock() {
+		t.Fatal("Second reader was not allowed")
+	}
+	if mu.TryLock() {
+		t.Fatal("Writer was allowed while readers hold the mutex")
+	}
+
+	mu.RUnlock()
+	mu.RUnlock()
+	if !mu.TryLock() {
+		t.Error("Writer was not allowed after readers released the mutex")
+	}
+}
+
+func TestRWMutex_writerBlocksNewReaders(t *testing.T) {
+	var mu async.RWMutex
+	mu.RLock()
+
+	ch := make(chan error, 1)
+	go func() { ch <- mu.LockContext(context.TODO()) }()
+	time.Sleep(time.Millisecond)
+
+	// Waiting writer is not starved by new readers.
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+	if err := mu.RLockContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error of the reader: %#v", err)
+	}
+
+	mu.RUnlock()
+	if err := <-ch; err != nil {
+		t.Errorf("Unexpected error of the writer: %#v", err)
+	}
+	mu.Unlock()
+}
+
+func TestRWMutex_RLocker(t *testing.T) {
+	var mu async.RWMutex
+
+	locker := mu.RLocker()
+	locker.Lock()
+	locker.Lock()
+
+	if mu.TryLock() {
+		t.Fatal("Writer was allowed while readers hold the mutex")
+	}
+
+	locker.Unlock()
+	locker.Unlock()
+	if !mu.TryLock() {
+		t.Error("Writer was not allowed after readers released the mutex")
+	}
+}
+
+func TestRWMutex_LockChan(t *testing.T) {
+	var mu async.RWMutex
+	mu.RLock()
+
+	chLocked, _ := mu.LockChan()
+	chRLocked, _ := mu.RLockChan()
+
+	// Waiting writer blocks new readers.
+	mu.RUnlock()
+	<-chLocked
+	select {
+	case <-chRLocked:
+		t.Fatal("Reader was allowed while writer holds the mutex")
+	default:
+	}
+
+	mu.Unlock()
+	<-chRLocked
+	mu.RUnlock()
+}
+
+func TestRWMutex_LockChanCancelled(t *testing.T) {
+	var mu async.RWMutex
+	mu.RLock()
+
+	_, cancelLock := mu.LockChan()
+	chRLocked, _ := mu.RLockChan()
+
+	// Reader waiting behind cancelled writer is allowed.
+	cancelLock()
+	<-chRLocked
+
+	mu.RUnlock()
+	mu.RUnlock()
+
+	// Lock acquired after caller has stopped waiting is unlocked on cancellation.
+	_, cancelLock = mu.LockChan()
+	cancelLock()
+
+	if !mu.TryRLock() {
+		t.Error("Mutex was not unlocked on cancellation")
+	}
+}
+
+func TestRWMutex_RUnlockPanicsIfLockedForWriting(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrMutexNotLocked {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	var mu async.RWMutex
+	mu.Lock()
+	mu.RUnlock()
+}
+
+func TestRWMutex_UnlockPanicsIfLockedForReading(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrMutexNotLocked {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	var mu async.RWMutex
+	mu.RLock()
+	mu.Unlock()
+}
@@ -0,0 +1,124 @@
+package async
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// PanicError is an error produced when asynchronous function panics.
+type PanicError struct {
+	// Value passed to panic().
+	Value any
+
+	// Stack of the goroutine captured when panic was recovered.
+	Stack []byte
+
+	// Task is the name of asynchronous function which panicked.
+	Task string
+}
+
+// newPanicError creates PanicError from argument of a recovered panic.
+func newPanicError(panicArg any, task string) *PanicError {
+	return &PanicError{
+		Value: panicArg,
+		Stack: debug.Stack(),
+		Task:  task,
+	}
+}
+
+// taskName returns the name of function f to identify it in PanicError.
+func taskName(f any) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
+
+func (e *PanicError) Error() string {
+	if e.Task == "" {
+		return fmt.Sprintf("asynchronous function panicked: %v", e.Value)
+	}
+	return fmt.Sprintf("asynchronous function %s panicked: %v", e.Task, e.Value)
+}
+
+// Unwrap returns the value passed to panic() if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Format implements fmt.Formatter.
+// Verb %+v prints error message followed by the stack captured when panic was recovered.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s\n%s", e.Error(), e.Stack)
+	case verb == 'v' || verb == 's':
+		fmt.Fprint(s, e.Error())
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(*async.PanicError=%s)", verb, e.Error())
+	}
+}
+
+// PanicMode defines how panic of an asynchronous function is passed to the caller.
+type PanicMode int
+
+// Supported panic modes.
+const (
+	// PanicToError converts panic into PanicError, which is returned as a regular error.
+	PanicToError PanicMode = iota
+
+	// PanicOnAwait converts panic into PanicError and panics with it again when result is awaited.
+	// For groups, method Execute() is considered to be awaiting results as well.
+	PanicOnAwait
+)
+
+// PanicPolicy defines what happens when asynchronous function panics.
+// Zero value converts panics into errors.
+type PanicPolicy struct {
+	// Mode defines how panic is passed to the caller.
+	Mode PanicMode
+
+	// Handler is called for every recovered panic, if set.
+	// It is called from the goroutine of the panicked function, before result is passed to the caller.
+	Handler func(*PanicError)
+}
+
+// Package level panic policy.
+var defaultPanicPolicy atomic.Value
+
+// SetPanicPolicy sets package level panic policy.
+// Policy applies to all asynchronous functions launched afterwards, unless overridden (e.g. by Group.SetPanicPolicy()).
+func SetPanicPolicy(policy PanicPolicy) {
+	defaultPanicPolicy.Store(policy)
+}
+
+// currentPanicPolicy returns package level panic policy.
+func currentPanicPolicy() PanicPolicy {
+	policy, _ := defaultPanicPolicy.Load().(PanicPolicy)
+	return policy
+}
+
+// handle argument of a recovered panic according to the policy.
+// Must be called from the deferred function, which recovered panic, for the stack to point to the panic location.
+//
+// Panic which is already PanicError was re-raised on await inside another asynchronous function,
+// it is passed as is, without calling the handler again.
+func (p PanicPolicy) handle(panicArg any, task string) *PanicError {
+	if err, ok := panicArg.(*PanicError); ok {
+		return err
+	}
+
+	err := newPanicError(panicArg, task)
+	if p.Handler != nil {
+		p.Handler(err)
+	}
+	return err
+}
@@ -0,0 +1,239 @@
+package async_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ error         = &async.PanicError{}
+	_ fmt.Formatter = &async.PanicError{}
+)
+
+func ExamplePanicError() {
+	promise := async.Execute(func() (string, error) {
+		panic("something went wrong")
+	})
+
+	_, err := promise()
+
+	var panicError *async.PanicError
+	if errors.As(err, &panicError) {
+		fmt.Println("Panic value:", panicError.Value)
+	}
+
+	// Output:
+	// Panic value: something went wrong
+}
+
+func panickingTask() (int, error) {
+	panic(customErrorType1{"custom panic"})
+}
+
+func TestPanicError_fromExecute(t *testing.T) {
+	_, err := async.Execute(panickingTask)()
+
+	var panicError *async.PanicError
+	if !errors.As(err, &panicError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+
+	if panicError.Value != (customErrorType1{"custom panic"}) {
+		t.Errorf("Unexpected panic value: %#v", panicError.Value)
+	}
+	if !strings.HasSuffix(panicError.Task, ".panickingTask") {
+		t.Errorf("Unexpected task name: %s", panicError.Task)
+	}
+	if !strings.Contains(string(panicError.Stack), "panic_test.go") {
+		t.Errorf("Stack does not point to panic location:\n%s", panicError.Stack)
+	}
+
+	// Original error is still discoverable.
+	var customError customErrorType1
+	if !errors.As(err, &customError) || customError.msg != "custom panic" {
+		t.Errorf("Unexpected error unwrapped from panic error: %#v", customError)
+	}
+}
+
+func TestPanicError_fromGroup(t *testing.T) {
+	var group async.Group
+
+	async.AddToExecutionGroup(&group, func() (interface{}, error) {
+		panic(42)
+	})
+
+	err := group.Execute()
+
+	var panicError *async.PanicError
+	if !err.Find(&panicError) {
+		t.Fatalf("Unexpected error received from the execution group: %#v", err)
+	}
+	if panicError.Value != 42 {
+		t.Errorf("Unexpected panic value: %#v", panicError.Value)
+	}
+	if !strings.Contains(string(panicError.Stack), "panic_test.go") {
+		t.Errorf("Stack does not point to panic location:\n%s", panicError.Stack)
+	}
+}
+
+func TestPanicError_fromResultGroup(t *testing.T) {
+	var group async.ResultGroup[int]
+
+	group.Add(panickingTask)
+
+	_, err := group.Execute()
+
+	var panicError *async.PanicError
+	if !err.Find(&panicError) {
+		t.Fatalf("Unexpected error received from the execution group: %#v", err)
+	}
+	if !strings.HasSuffix(panicError.Task, ".panickingTask") {
+		t.Errorf("Unexpected task name: %s", panicError.Task)
+	}
+}
+
+func TestPanicError_Format(t *testing.T) {
+	err := &async.PanicError{Value: "panic happened", Stack: []byte("dummy stack"), Task: "dummy.task"}
+
+	if s := fmt.Sprintf("%v", err); s != "asynchronous function dummy.task panicked: panic happened" {
+		t.Errorf("Unexpected %%v format: %s", s)
+	}
+	if s := fmt.Sprintf("%+v", err); s != "asynchronous function dummy.task panicked: panic happened\ndummy stack" {
+		t.Errorf("Unexpected %%+v format: %s", s)
+	}
+}
+
+func ExampleSetPanicPolicy() {
+	async.SetPanicPolicy(async.PanicPolicy{
+		Mode: async.PanicToError,
+		Handler: func(err *async.PanicError) {
+			// Report panic to alerting system.
+			fmt.Println("Panic reported:", err.Value)
+		},
+	})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	_, err := async.Execute(func() (string, error) {
+		panic("something went wrong")
+	})()
+	fmt.Println("Error is panic:", errors.As(err, new(*async.PanicError)))
+
+	// Output:
+	// Panic reported: something went wrong
+	// Error is panic: true
+}
+
+// recoverPanicError calls function f and returns PanicError it panicked with.
+func recoverPanicError(f func()) (panicErr *async.PanicError) {
+	defer func() {
+		panicErr, _ = recover().(*async.PanicError)
+	}()
+
+	f()
+	return nil
+}
+
+func TestSetPanicPolicy_panicOnAwaitForExecute(t *testing.T) {
+	async.SetPanicPolicy(async.PanicPolicy{Mode: async.PanicOnAwait})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	promise := async.Execute(func() (interface{}, error) {
+		panic("panic happened")
+	})
+
+	panicErr := recoverPanicError(func() { _, _ = promise() })
+	if panicErr == nil || panicErr.Value != "panic happened" {
+		t.Errorf("Unexpected panic raised by the promise: %#v", panicErr)
+	}
+}
+
+func TestSetPanicPolicy_panicOnAwaitForFuture(t *testing.T) {
+	async.SetPanicPolicy(async.PanicPolicy{Mode: async.PanicOnAwait})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	future := async.ExecuteFuture(func() (interface{}, error) {
+		panic("panic happened")
+	})
+
+	// Panic is raised on every await.
+	for i := 0; i < 2; i++ {
+		panicErr := recoverPanicError(func() { _, _ = future.Await() })
+		if panicErr == nil || panicErr.Value != "panic happened" {
+			t.Errorf("Unexpected panic raised by the future: %#v", panicErr)
+		}
+	}
+}
+
+func TestSetPanicPolicy_handlerIsCalledOnceForNestedExecution(t *testing.T) {
+	var calls int32
+	async.SetPanicPolicy(async.PanicPolicy{
+		Mode:    async.PanicOnAwait,
+		Handler: func(*async.PanicError) { atomic.AddInt32(&calls, 1) },
+	})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	promise := async.All(async.Execute(func() (interface{}, error) {
+		panic("panic happened")
+	}))
+
+	panicErr := recoverPanicError(func() { _, _ = promise() })
+	if panicErr == nil || panicErr.Value != "panic happened" {
+		t.Errorf("Unexpected panic raised by the promise: %#v", panicErr)
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("Panic handler was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestGroup_SetPanicPolicy(t *testing.T) {
+	var calls int32
+
+	// Group policy overrides package level policy.
+	async.SetPanicPolicy(async.PanicPolicy{
+		Handler: func(*async.PanicError) { t.Error("Package level panic handler was called") },
+	})
+	defer async.SetPanicPolicy(async.PanicPolicy{})
+
+	var group async.Group
+	group.SetPanicPolicy(async.PanicPolicy{
+		Mode:    async.PanicOnAwait,
+		Handler: func(*async.PanicError) { atomic.AddInt32(&calls, 1) },
+	})
+
+	promise := async.AddToExecutionGroup(&group, func() (interface{}, error) {
+		panic("panic happened")
+	})
+
+	panicErr := recoverPanicError(func() { group.Execute() })
+	if panicErr == nil || panicErr.Value != "panic happened" {
+		t.Errorf("Unexpected panic raised by the execution group: %#v", panicErr)
+	}
+
+	panicErr = recoverPanicError(func() { promise() })
+	if panicErr == nil || panicErr.Value != "panic happened" {
+		t.Errorf("Unexpected panic raised by the promise: %#v", panicErr)
+	}
+
+	if calls := atomic.LoadInt32(&calls); calls != 1 {
+		t.Errorf("Panic handler was called unexpected number of times: %d", calls)
+	}
+}
+
+func TestResultGroup_SetPanicPolicy(t *testing.T) {
+	var group async.ResultGroup[int]
+	group.SetPanicPolicy(async.PanicPolicy{Mode: async.PanicOnAwait})
+
+	group.Add(panickingTask)
+
+	panicErr := recoverPanicError(func() { group.Execute() })
+	if panicErr == nil || panicErr.Value != (customErrorType1{"custom panic"}) {
+		t.Errorf("Unexpected panic raised by the execution group: %#v", panicErr)
+	}
+}
@@ -0,0 +1,330 @@
+package async
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Default time after which idle extra workers of an elastic pool exit.
+const defaultPoolIdleTimeout = time.Minute
+
+// PoolConfig configures worker pool.
+type PoolConfig struct {
+	// Workers is the number of workers which are always running, must be greater than 0.
+	Workers int
+
+	// MaxWorkers is the maximum number of workers, makes pool elastic if greater than Workers.
+	// Extra workers are launched when queue is full and exit after being idle for IdleTimeout.
+	MaxWorkers int
+
+	// IdleTimeout after which extra workers exit, 1 minute by default.
+	IdleTimeout time.Duration
+
+	// QueueSize is the number of tasks which can wait for a free worker.
+	// With zero queue size, task is accepted only if there is an idle worker (or pool can launch an extra one).
+	QueueSize int
+
+	// RateLimiter limits the rate at which tasks are executed, no limit if nil.
+	// Workers wait for the limiter before executing each task.
+	RateLimiter RateLimiter
+
+	// Semaphore limits the total weight of tasks executing at the same time, no limit if nil.
+	// Workers acquire semaphore with task's weight before executing it, see SubmitWeighted().
+	Semaphore *Semaphore
+}
+
+// Pool executes functions asynchronously using a limited number of workers.
+// Functions wait for a free worker in a bounded queue.
+type Pool struct {
+	config PoolConfig
+
+	queue   chan poolTask
+	workers int32
+	wg      sync.WaitGroup
+
+	// Mutex protects closed flag, submissions hold read lock while sending to the queue.
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+	quit      chan struct{}
+
+	// Pending tasks are discarded instead of being executed, see ShutdownNow().
+	// Context is cancelled to interrupt workers waiting for rate limiter.
+	discard int32
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+type poolTask struct {
+	run    func()
+	fail   func(error)
+	weight int
+}
+
+// NewPool creates worker pool and launches its workers.
+// Panics with ErrInvalidPoolConfig if config.Workers is less than 1 or config.QueueSize is negative.
+func NewPool(config PoolConfig) *Pool {
+	if config.Workers < 1 || config.QueueSize < 0 {
+		panic(ErrInvalidPoolConfig)
+	}
+	if config.MaxWorkers < config.Workers {
+		config.MaxWorkers = config.Workers
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultPoolIdleTimeout
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p := &Pool{
+		config:  config,
+		queue:   make(chan poolTask, config.QueueSize),
+		workers: int32(config.Workers),
+		quit:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	p.wg.Add(config.Workers)
+	for i := 0; i < config.Workers; i++ {
+		go p.worker(nil, false)
+	}
+
+	return p
+}
+
+// Submit function f for asynchronous execution by the pool.
+// Returns promise which can be called to retrieve function's f result.
+// Blocks while queue is full, returns ErrPoolClosed if pool was shut down.
+//
+// Promise can be called only once.
+// Calling promise repeatedly will result in an error.
+// Promise returns ErrPoolClosed if function was discarded by ShutdownNow().
+func Submit[T any](p *Pool, f func() (T, error)) (PromiseWithError[T], error) {
+	return SubmitContext(context.Background(), p, f)
+}
+
+// SubmitContext submits function f for asynchronous execution by the pool.
+// Same as Submit(), but stops waiting for a free place in the queue when context is done and returns context's error.
+func SubmitContext[T any](ctx context.Context, p *Pool, f func() (T, error)) (PromiseWithError[T], error) {
+	return SubmitWeighted(ctx, p, 1, f)
+}
+
+// SubmitWeighted submits function f with the weight for asynchronous execution by the pool.
+// Same as SubmitContext(), but worker acquires pool's semaphore with the weight before executing function f.
+// Returns ErrSemaphoreWeightExceedsSize if weight exceeds size of pool's semaphore.
+// Returns ErrInvalidSemaphoreWeight if weight is less than 1.
+func SubmitWeighted[T any](ctx context.Context, p *Pool, weight int, f func() (T, error)) (PromiseWithError[T], error) {
+	if weight < 1 {
+		return nil, ErrInvalidSemaphoreWeight
+	}
+	if p.config.Semaphore != nil && weight > p.config.Semaphore.size {
+		return nil, ErrSemaphoreWeightExceedsSize
+	}
+
+	task, promise := newPoolTask(weight, f)
+	if err := p.submit(ctx, task, true); err != nil {
+		return nil, err
+	}
+	return promise, nil
+}
+
+// TrySubmit submits function f for asynchronous execution by the pool.
+// Same as Submit(), but returns ErrPoolQueueFull immediately if queue is full.
+func TrySubmit[T any](p *Pool, f func() (T, error)) (PromiseWithError[T], error) {
+	task, promise := newPoolTask(1, f)
+	if err := p.submit(context.Background(), task, false); err != nil {
+		return nil, err
+	}
+	return promise, nil
+}
+
+// Shutdown stops accepting new functions and waits until all submitted functions have executed.
+// Returns context's error if context is done first, pool keeps executing remaining functions in this case.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.close()
+	return p.wait(ctx)
+}
+
+// ShutdownNow stops accepting new functions, discards functions waiting in the queue
+// and waits until functions which are already executing have completed.
+// Promises of discarded functions return ErrPoolClosed.
+// Returns context's error if context is done first.
+func (p *Pool) ShutdownNow(ctx context.Context) error {
+	atomic.StoreInt32(&p.discard, 1)
+	p.cancel()
+	p.close()
+	return p.wait(ctx)
+}
+
+// newPoolTask wraps function f with the weight into a pool task.
+// Returns task and promise to retrieve function's f result.
+func newPoolTask[T any](weight int, f func() (T, error)) (poolTask, PromiseWithError[T]) {
+	task := taskName(f)
+	policy := currentPanicPolicy()
+
+	// This channel is buffered. It will be written to only once.
+	// That way worker never blocks on it (even if promise is never called and channel not drained).
+	ch := make(chan executeChannelMessageType[T], 1)
+
+	return poolTask{
+		run: func() {
+			defer close(ch)
+			ch <- call(task, policy, f)
+		},
+		fail: func(err error) {
+			defer close(ch)
+			ch <- executeChannelMessageType[T]{err: err}
+		},
+		weight: weight,
+	}, newPromise(ch)
+}
+
+// submit task to the queue.
+// If block is false, returns ErrPoolQueueFull instead of waiting for a free place in the queue.
+func (p *Pool) submit(ctx context.Context, task poolTask, block bool) error {
+	// Read lock prevents pool from closing the queue while task is being sent.
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+
+	select {
+	case p.queue <- task:
+		return nil
+	default:
+	}
+
+	// Queue is full, try to launch an extra worker to execute this task.
+	if p.grow(task) {
+		return nil
+	}
+
+	if !block {
+		return ErrPoolQueueFull
+	}
+
+	select {
+	case p.queue <- task:
+		return nil
+	case <-p.quit:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// grow launches an extra worker with the task if maximum number of workers is not reached yet.
+// Must be called while holding read lock.
+func (p *Pool) grow(task poolTask) bool {
+	for {
+		workers := atomic.LoadInt32(&p.workers)
+		if workers >= int32(p.config.MaxWorkers) {
+			return false
+		}
+		if atomic.CompareAndSwapInt32(&p.workers, workers, workers+1) {
+			break
+		}
+	}
+
+	p.wg.Add(1)
+	go p.worker(&task, true)
+
+	return true
+}
+
+// worker executes tasks from the queue until queue is closed.
+// Extra worker also exits after being idle for configured timeout.
+func (p *Pool) worker(task *poolTask, extra bool) {
+	defer p.wg.Done()
+	defer atomic.AddInt32(&p.workers, -1)
+
+	if task != nil {
+		p.run(*task)
+	}
+
+	// Single timer is reused, since timers are not garbage collected until they fire before Go 1.23.
+	var timer *time.Timer
+	var idle <-chan time.Time
+	if extra {
+		timer = time.NewTimer(p.config.IdleTimeout)
+		defer timer.Stop()
+		idle = timer.C
+	}
+
+	for {
+		select {
+		case task, ok := <-p.queue:
+			if !ok {
+				return
+			}
+			p.run(task)
+
+			if extra {
+				// Timer could have fired while running the task, drain its channel before reset.
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(p.config.IdleTimeout)
+			}
+		case <-idle:
+			return
+		}
+	}
+}
+
+// run task unless pool is discarding pending tasks.
+// Waits for rate limiter and acquires semaphore first, task is discarded if pool starts discarding while waiting.
+func (p *Pool) run(task poolTask) {
+	if atomic.LoadInt32(&p.discard) == 1 || waitRateLimit(p.ctx, p.config.RateLimiter) != nil {
+		task.fail(ErrPoolClosed)
+		return
+	}
+
+	if semaphore := p.config.Semaphore; semaphore != nil {
+		if err := semaphore.Acquire(p.ctx, task.weight); err != nil {
+			task.fail(ErrPoolClosed)
+			return
+		}
+		defer semaphore.Release(task.weight)
+	}
+
+	task.run()
+}
+
+// close stops accepting new tasks and closes the queue, so workers exit after draining it.
+func (p *Pool) close() {
+	p.closeOnce.Do(func() {
+		// Wake up blocked submissions, so they release read lock.
+		close(p.quit)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		p.closed = true
+		close(p.queue)
+	})
+}
+
+// wait until all workers have exited or context is done.
+func (p *Pool) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,392 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExamplePool() {
+	pool := async.NewPool(async.PoolConfig{Workers: 2, QueueSize: 10})
+	defer pool.Shutdown(context.TODO())
+
+	var promises []async.PromiseWithError[int]
+	for i := 1; i <= 3; i++ {
+		i := i
+		promise, err := async.Submit(pool, func() (int, error) {
+			// Perform some lengthy operation.
+
+			return i * 10, nil
+		})
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		promises = append(promises, promise)
+	}
+
+	for _, promise := range promises {
+		res, err := promise()
+		fmt.Println("Result:", res, "error:", err)
+	}
+
+	// Output:
+	// Result: 10 error: <nil>
+	// Result: 20 error: <nil>
+	// Result: 30 error: <nil>
+}
+
+func TestPool_limitsConcurrency(t *testing.T) {
+	const workers = 2
+
+	pool := async.NewPool(async.PoolConfig{Workers: workers, QueueSize: 10})
+	defer pool.Shutdown(context.TODO())
+
+	var mu sync.Mutex
+	var running, maxRunning int
+
+	var promises []async.PromiseWithError[int]
+	for i := 0; i < 6; i++ {
+		promise, err := async.Submit(pool, func() (int, error) {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+
+			return 0, nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error received on submission: %#v", err)
+		}
+		promises = append(promises, promise)
+	}
+
+	for _, promise := range promises {
+		if _, err := promise(); err != nil {
+			t.Errorf("Unexpected error received from the promise: %#v", err)
+		}
+	}
+
+	if maxRunning != workers {
+		t.Errorf("Unexpected number of functions running at the same time: %d", maxRunning)
+	}
+}
+
+// blockPool occupies all workers and queue of the pool until returned function is called.
+func blockPool(t *testing.T, pool *async.Pool, tasks int) func() {
+	chRelease := make(chan struct{})
+
+	for i := 0; i < tasks; i++ {
+		_, err := async.Submit(pool, func() (interface{}, error) {
+			<-chRelease
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error received on submission: %#v", err)
+		}
+	}
+
+	return func() { close(chRelease) }
+}
+
+func TestTrySubmit_failsWhenQueueIsFull(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1, QueueSize: 1})
+	defer pool.Shutdown(context.TODO())
+
+	release := blockPool(t, pool, 2)
+	defer release()
+
+	promise, err := async.TrySubmit(pool, func() (interface{}, error) {
+		return nil, nil
+	})
+	if promise != nil {
+		t.Error("Unexpected promise received on failed submission")
+	}
+	if !errors.Is(err, async.ErrPoolQueueFull) {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+}
+
+func TestSubmitContext_respectsContextWhenQueueIsFull(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1})
+	defer pool.Shutdown(context.TODO())
+
+	release := blockPool(t, pool, 1)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := async.SubmitContext(ctx, pool, func() (interface{}, error) {
+		return nil, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+}
+
+func TestPool_elasticWorkers(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1, MaxWorkers: 3, IdleTimeout: time.Millisecond})
+	defer pool.Shutdown(context.TODO())
+
+	// Extra workers are launched instead of queueing.
+	release := blockPool(t, pool, 3)
+
+	_, err := async.TrySubmit(pool, func() (interface{}, error) {
+		return nil, nil
+	})
+	if !errors.Is(err, async.ErrPoolQueueFull) {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+
+	release()
+
+	// Wait for the extra workers to exit and check that pool can grow again.
+	time.Sleep(10 * time.Millisecond)
+
+	release = blockPool(t, pool, 3)
+	release()
+}
+
+func TestPool_Shutdown(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1, QueueSize: 10})
+
+	var executed int32
+	var promises []async.PromiseWithError[interface{}]
+	for i := 0; i < 3; i++ {
+		promise, err := async.Submit(pool, func() (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&executed, 1)
+			return nil, nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error received on submission: %#v", err)
+		}
+		promises = append(promises, promise)
+	}
+
+	if err := pool.Shutdown(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+
+	// All submitted functions have executed.
+	if executed := atomic.LoadInt32(&executed); executed != 3 {
+		t.Errorf("Unexpected number of executed functions: %d", executed)
+	}
+	for _, promise := range promises {
+		if _, err := promise(); err != nil {
+			t.Errorf("Unexpected error received from the promise: %#v", err)
+		}
+	}
+
+	_, err := async.Submit(pool, func() (interface{}, error) {
+		return nil, nil
+	})
+	if !errors.Is(err, async.ErrPoolClosed) {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+}
+
+func TestPool_ShutdownNow(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1, QueueSize: 10})
+
+	chStarted := make(chan struct{})
+	chRelease := make(chan struct{})
+
+	running, err := async.Submit(pool, func() (string, error) {
+		close(chStarted)
+		<-chRelease
+		return "dummy result", nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error received on submission: %#v", err)
+	}
+
+	pending, err := async.Submit(pool, func() (string, error) {
+		t.Error("Pending function was executed after ShutdownNow()")
+		return "", nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error received on submission: %#v", err)
+	}
+
+	<-chStarted
+
+	chShutdown := make(chan error)
+	go func() { chShutdown <- pool.ShutdownNow(context.TODO()) }()
+
+	// Running function is not interrupted.
+	time.Sleep(time.Millisecond)
+	close(chRelease)
+
+	if err := <-chShutdown; err != nil {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+
+	if res, err := running(); res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result received from the running promise: %#v, %#v", res, err)
+	}
+	if _, err := pending(); !errors.Is(err, async.ErrPoolClosed) {
+		t.Errorf("Unexpected error received from the pending promise: %#v", err)
+	}
+}
+
+func TestPool_ShutdownRespectsContext(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1})
+
+	release := blockPool(t, pool, 1)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+}
+
+func TestPool_RateLimiter(t *testing.T) {
+	clock := newFakeClock()
+	pool := async.NewPool(async.PoolConfig{
+		Workers:   2,
+		QueueSize: 3,
+		RateLimiter: async.NewTokenBucket(async.TokenBucketConfig{
+			Interval: time.Second,
+			Burst:    1,
+			Clock:    clock,
+		}),
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := async.Submit(pool, func() (interface{}, error) { return nil, nil }); err != nil {
+			t.Fatalf("Unexpected error received on submission: %#v", err)
+		}
+	}
+
+	if err := pool.Shutdown(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+	if delays := clock.Delays(); len(delays) != 2 {
+		t.Errorf("Unexpected delays of rate limiter: %v", delays)
+	}
+}
+
+func TestPool_ShutdownNowInterruptsRateLimiter(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{
+		Workers:   1,
+		QueueSize: 1,
+		RateLimiter: async.NewTokenBucket(async.TokenBucketConfig{
+			Interval: time.Hour,
+			Burst:    1,
+		}),
+	})
+
+	first, _ := async.Submit(pool, func() (string, error) { return "dummy result", nil })
+	second, _ := async.Submit(pool, func() (string, error) {
+		t.Error("Function was executed despite rate limit")
+		return "", nil
+	})
+
+	if res, err := first(); res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result received from the promise: %#v, %#v", res, err)
+	}
+
+	if err := pool.ShutdownNow(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+	if _, err := second(); !errors.Is(err, async.ErrPoolClosed) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
+
+func TestPool_Semaphore(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+	pool := async.NewPool(async.PoolConfig{Workers: 2, QueueSize: 2, Semaphore: semaphore})
+
+	if _, err := async.SubmitWeighted(context.TODO(), pool, 11, func() (interface{}, error) {
+		return nil, nil
+	}); err != async.ErrSemaphoreWeightExceedsSize {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+	if _, err := async.SubmitWeighted(context.TODO(), pool, 0, func() (interface{}, error) {
+		return nil, nil
+	}); err != async.ErrInvalidSemaphoreWeight {
+		t.Errorf("Unexpected error received on submission: %#v", err)
+	}
+
+	var promises []async.PromiseWithError[bool]
+	for i := 0; i < 2; i++ {
+		promise, err := async.SubmitWeighted(context.TODO(), pool, 10, func() (bool, error) {
+			// Semaphore is fully acquired by this function.
+			acquired := semaphore.TryAcquire(1)
+			time.Sleep(time.Millisecond)
+			return acquired, nil
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error received on submission: %#v", err)
+		}
+		promises = append(promises, promise)
+	}
+
+	for _, promise := range promises {
+		if acquired, err := promise(); acquired || err != nil {
+			t.Errorf("Unexpected result received from the promise: %#v, %#v", acquired, err)
+		}
+	}
+
+	if err := pool.Shutdown(context.TODO()); err != nil {
+		t.Errorf("Unexpected error received on shutdown: %#v", err)
+	}
+}
+
+func TestPool_handlesPanics(t *testing.T) {
+	pool := async.NewPool(async.PoolConfig{Workers: 1})
+	defer pool.Shutdown(context.TODO())
+
+	promise, err := async.Submit(pool, func() (interface{}, error) {
+		panic(dummyError)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error received on submission: %#v", err)
+	}
+
+	var panicError *async.PanicError
+	if _, err := promise(); !errors.As(err, &panicError) || !errors.Is(err, dummyError) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	// Worker survives panic.
+	promise, err = async.Submit(pool, func() (interface{}, error) {
+		return "dummy result", nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error received on submission: %#v", err)
+	}
+	if res, err := promise(); res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result received from the promise: %#v, %#v", res, err)
+	}
+}
+
+func TestNewPool_panicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidPoolConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewPool(async.PoolConfig{})
+}
@@ -1,9 +1,17 @@
 package async
 
+import (
+	"context"
+)
+
 type (
 	// Promise will return result of an asynchronous execution.
 	Promise[T any] func() T
 
 	// PromiseWithError will return result of an asynchronous execution or an encountered error.
 	PromiseWithError[T any] func() (T, error)
+
+	// PromiseWithContext will return result of an asynchronous execution or an encountered error.
+	// Waiting for the result is aborted when context is done.
+	PromiseWithContext[T any] func(context.Context) (T, error)
 )
@@ -0,0 +1,396 @@
+package async
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate of events, e.g. function calls.
+type RateLimiter interface {
+	// Wait blocks until an event is allowed or context is done.
+	// Returns context's error if context is done first.
+	Wait(ctx context.Context) error
+
+	// Allow reports whether an event may happen now, consuming a permit if it does.
+	Allow() bool
+
+	// Reserve permits for n events.
+	// Returns the delay after which the events may happen, the caller must wait for it.
+	// Returns ErrReservationExceedsLimit if n exceeds limiter's capacity, nothing is reserved in this case.
+	Reserve(n int) (time.Duration, error)
+}
+
+// TokenBucketConfig configures token bucket rate limiter.
+type TokenBucketConfig struct {
+	// Interval after which a token is added to the bucket, must be greater than 0.
+	Interval time.Duration
+
+	// Burst is the capacity of the bucket, must be greater than 0.
+	// Bucket is full initially, so up to Burst events are allowed at once.
+	Burst int
+
+	// Clock to measure time and wait for tokens, SystemClock is used if nil.
+	Clock Clock
+}
+
+// TokenBucket is a rate limiter, which allows events while there are tokens in the bucket.
+// Each event consumes a token, tokens are added at a constant rate.
+type TokenBucket struct {
+	config TokenBucketConfig
+	clock  Clock
+
+	mu sync.Mutex
+
+	// Number of tokens at the time of the last update.
+	// Negative number means that tokens are reserved in advance.
+	tokens float64
+	last   time.Time
+
+	// Sequence number of the latest reservation.
+	reservation uint64
+}
+
+// SlidingWindowConfig configures sliding window rate limiter.
+type SlidingWindowConfig struct {
+	// Limit is the number of events allowed within the window, must be greater than 0.
+	Limit int
+
+	// Window is the duration of the window, must be greater than 0.
+	Window time.Duration
+
+	// Clock to measure time and wait for the window to slide, SystemClock is used if nil.
+	Clock Clock
+}
+
+// SlidingWindow is a rate limiter, which allows up to a limited number of events within a sliding window.
+// Number of events within the window is estimated from counters of the current and the previous fixed windows,
+// weighting the previous counter by the part of the sliding window it overlaps.
+type SlidingWindow struct {
+	config SlidingWindowConfig
+	clock  Clock
+
+	mu    sync.Mutex
+	state slidingWindowState
+
+	// Time of the latest granted event, later than now if events are reserved in advance.
+	next time.Time
+}
+
+type slidingWindowState struct {
+	// Start of the current fixed window.
+	start time.Time
+
+	// Counters of the previous and the current fixed windows.
+	prev, curr int
+}
+
+// Ensure interface implementation
+var (
+	_ RateLimiter = &TokenBucket{}
+	_ RateLimiter = &SlidingWindow{}
+)
+
+// Throttle wraps function f with rate limiter.
+// Returned function waits for the limiter before calling f and returns limiter's error without calling f if waiting fails.
+func Throttle[T any](ctx context.Context, limiter RateLimiter, f func() (T, error)) func() (T, error) {
+	return func() (res T, err error) {
+		if err := limiter.Wait(ctx); err != nil {
+			return res, err
+		}
+		return f()
+	}
+}
+
+// NewTokenBucket creates token bucket rate limiter with a full bucket.
+// Panics with ErrInvalidRateLimiterConfig if config.Interval or config.Burst is not positive.
+func NewTokenBucket(config TokenBucketConfig) *TokenBucket {
+	if config.Interval <= 0 || config.Burst < 1 {
+		panic(ErrInvalidRateLimiterConfig)
+	}
+
+	clock := clockOrDefault(config.Clock)
+
+	return &TokenBucket{
+		config: config,
+		clock:  clock,
+		tokens: float64(config.Burst),
+		last:   clock.Now(),
+	}
+}
+
+// Wait blocks until a token is available or context is done.
+// Waiters receive tokens in the order they have called Wait.
+// If context is done first, reserved token is returned to the bucket unless there are later reservations.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	b.mu.Lock()
+	delay, reservation := b.reserve(1)
+	b.mu.Unlock()
+
+	return waitReservation(ctx, b.clock, delay, func() {
+		b.cancel(1, reservation)
+	})
+}
+
+// Allow reports whether a token is available now, consuming it if it is.
+func (b *TokenBucket) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance()
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// Reserve n tokens, see RateLimiter.Reserve().
+// Returns ErrReservationExceedsLimit if n exceeds burst.
+func (b *TokenBucket) Reserve(n int) (time.Duration, error) {
+	if n > b.config.Burst {
+		return 0, ErrReservationExceedsLimit
+	}
+	if n < 1 {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delay, _ := b.reserve(n)
+	return delay, nil
+}
+
+// reserve n tokens.
+// Returns the delay after which tokens may be used and sequence number of the reservation.
+// Must be called while holding the lock.
+func (b *TokenBucket) reserve(n int) (time.Duration, uint64) {
+	b.advance()
+	b.reservation++
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0, b.reservation
+	}
+
+	return time.Duration(math.Ceil(-b.tokens * float64(b.config.Interval))), b.reservation
+}
+
+// advance adds tokens accumulated since the last update.
+// Must be called while holding the lock.
+func (b *TokenBucket) advance() {
+	now := b.clock.Now()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.tokens+float64(elapsed)/float64(b.config.Interval), float64(b.config.Burst))
+		b.last = now
+	}
+}
+
+// cancel returns n tokens of the reservation to the bucket.
+// Tokens are returned only if there were no later reservations,
+// since later reservations have already been granted delays taking these tokens into account.
+func (b *TokenBucket) cancel(n int, reservation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if reservation != b.reservation {
+		return
+	}
+
+	b.advance()
+	b.tokens = math.Min(b.tokens+float64(n), float64(b.config.Burst))
+}
+
+// NewSlidingWindow creates sliding window rate limiter.
+// Panics with ErrInvalidRateLimiterConfig if config.Limit or config.Window is not positive.
+func NewSlidingWindow(config SlidingWindowConfig) *SlidingWindow {
+	if config.Limit < 1 || config.Window <= 0 {
+		panic(ErrInvalidRateLimiterConfig)
+	}
+
+	clock := clockOrDefault(config.Clock)
+	now := clock.Now()
+
+	return &SlidingWindow{
+		config: config,
+		clock:  clock,
+		state:  slidingWindowState{start: now},
+		next:   now,
+	}
+}
+
+// Wait blocks until an event is allowed within the window or context is done.
+// Waiters are allowed in the order they have called Wait.
+// If context is done first, reserved event is removed from the window.
+func (w *SlidingWindow) Wait(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	w.mu.Lock()
+	at := w.reserve(1)
+	w.mu.Unlock()
+
+	return waitReservation(ctx, w.clock, at.Sub(w.clock.Now()), func() {
+		w.cancel(1, at)
+	})
+}
+
+// Allow reports whether an event is allowed within the window now, counting it if it is.
+// Events reserved in advance take precedence.
+func (w *SlidingWindow) Allow() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.clock.Now()
+	if w.next.After(now) {
+		return false
+	}
+
+	at, state := w.find(1, now)
+	if at.After(now) {
+		return false
+	}
+
+	state.curr++
+	w.state = state
+	w.next = now
+
+	return true
+}
+
+// Reserve n events, see RateLimiter.Reserve().
+// Returns ErrReservationExceedsLimit if n exceeds the limit.
+func (w *SlidingWindow) Reserve(n int) (time.Duration, error) {
+	if n > w.config.Limit {
+		return 0, ErrReservationExceedsLimit
+	}
+	if n < 1 {
+		return 0, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	at := w.reserve(n)
+	if delay := at.Sub(w.clock.Now()); delay > 0 {
+		return delay, nil
+	}
+	return 0, nil
+}
+
+// reserve n events after the previously reserved ones.
+// Returns the time at which events are allowed.
+// Must be called while holding the lock.
+func (w *SlidingWindow) reserve(n int) time.Time {
+	t := w.clock.Now()
+	if w.next.After(t) {
+		t = w.next
+	}
+
+	at, state := w.find(n, t)
+	state.curr += n
+
+	w.state = state
+	w.next = at
+
+	return at
+}
+
+// find the earliest time not before t, at which n events are allowed.
+// Returns the time and the state of the window at that time, the limiter itself is not modified.
+// Must be called while holding the lock.
+func (w *SlidingWindow) find(n int, t time.Time) (time.Time, slidingWindowState) {
+	state := w.state
+	window := w.config.Window
+	limit := w.config.Limit
+
+	for {
+		state.slide(t, window)
+
+		if state.curr+n <= limit {
+			if state.prev == 0 {
+				return t, state
+			}
+
+			// Previous window's weight decreases as the window slides, find when it becomes small enough.
+			elapsed := 1 - float64(limit-n-state.curr)/float64(state.prev)
+			at := state.start.Add(time.Duration(math.Ceil(elapsed * float64(window))))
+			if !at.After(t) {
+				return t, state
+			}
+			if at.Before(state.start.Add(window)) {
+				return at, state
+			}
+		}
+
+		// Events are not allowed within the current fixed window, try the next one.
+		t = state.start.Add(window)
+	}
+}
+
+// cancel n events reserved at the time.
+func (w *SlidingWindow) cancel(n int, at time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	counter := &w.state.curr
+	if at.Before(w.state.start) {
+		if at.Before(w.state.start.Add(-w.config.Window)) {
+			return
+		}
+		counter = &w.state.prev
+	}
+
+	*counter -= n
+	if *counter < 0 {
+		*counter = 0
+	}
+}
+
+// slide fixed windows, so that the current one contains time t.
+func (s *slidingWindowState) slide(t time.Time, window time.Duration) {
+	elapsed := t.Sub(s.start)
+	if elapsed < window {
+		return
+	}
+
+	if elapsed < 2*window {
+		s.prev = s.curr
+	} else {
+		s.prev = 0
+	}
+	s.curr = 0
+	s.start = s.start.Add(elapsed / window * window)
+}
+
+// waitReservation waits for the delay or until context is done.
+// Calls cancel and returns context's error if context is done first.
+func waitReservation(ctx context.Context, clock Clock, delay time.Duration, cancel func()) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		cancel()
+		return ctx.Err()
+	}
+}
+
+// waitRateLimit waits for the limiter if it is set.
+func waitRateLimit(ctx context.Context, limiter RateLimiter) error {
+	if limiter == nil {
+		return nil
+	}
+	return limiter.Wait(ctx)
+}
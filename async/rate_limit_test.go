@@ -0,0 +1,304 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleTokenBucket() {
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Millisecond,
+		Burst:    2,
+	})
+
+	var group async.Group
+	group.SetRateLimit(limiter)
+
+	for i := 1; i <= 3; i++ {
+		i := i
+		async.AddToExecutionGroup(&group, func() (interface{}, error) {
+			// Call rate limited API.
+			fmt.Println("Request sent:", i)
+			return nil, nil
+		})
+	}
+
+	if err := group.Execute(); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Unordered output:
+	// Request sent: 1
+	// Request sent: 2
+	// Request sent: 3
+}
+
+func ExampleThrottle() {
+	limiter := async.NewSlidingWindow(async.SlidingWindowConfig{
+		Limit:  10,
+		Window: time.Second,
+	})
+
+	call := async.Throttle(context.TODO(), limiter, func() (string, error) {
+		// Call rate limited API.
+
+		return "string result of rate limited call", nil
+	})
+
+	res, err := async.Execute(call)()
+	fmt.Println("Result:", res)
+	fmt.Println("Error:", err)
+
+	// Output:
+	// Result: string result of rate limited call
+	// Error: <nil>
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	clock := newFakeClock()
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Second,
+		Burst:    2,
+		Clock:    clock,
+	})
+
+	expected := []bool{true, true, false}
+	var allowed []bool
+	for i := 0; i < 3; i++ {
+		allowed = append(allowed, limiter.Allow())
+	}
+	if !reflect.DeepEqual(allowed, expected) {
+		t.Errorf("Unexpected events allowed: %v", allowed)
+	}
+
+	clock.Advance(time.Second)
+	if !limiter.Allow() {
+		t.Error("Token was not added to the bucket")
+	}
+}
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Second,
+		Burst:    3,
+		Clock:    newFakeClock(),
+	})
+
+	if delay, err := limiter.Reserve(3); delay != 0 || err != nil {
+		t.Errorf("Unexpected reservation: %s, %#v", delay, err)
+	}
+	if delay, err := limiter.Reserve(2); delay != 2*time.Second || err != nil {
+		t.Errorf("Unexpected reservation: %s, %#v", delay, err)
+	}
+	if _, err := limiter.Reserve(4); err != async.ErrReservationExceedsLimit {
+		t.Errorf("Unexpected reservation error: %#v", err)
+	}
+	if limiter.Allow() {
+		t.Error("Event was allowed before reserved ones")
+	}
+}
+
+func TestTokenBucket_Wait(t *testing.T) {
+	clock := newFakeClock()
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Second,
+		Burst:    1,
+		Clock:    clock,
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.TODO()); err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	}
+
+	expected := []time.Duration{time.Second, time.Second}
+	if delays := clock.Delays(); !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Unexpected delays: %v", delays)
+	}
+}
+
+func TestTokenBucket_WaitReturnsTokenOnCancellation(t *testing.T) {
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Hour,
+		Burst:    1,
+	})
+	limiter.Allow()
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if delay, _ := limiter.Reserve(1); delay > time.Hour {
+		t.Errorf("Token was not returned to the bucket, delay: %s", delay)
+	}
+}
+
+func TestTokenBucket_WaitKeepsTokenOnCancellationAfterLaterReservation(t *testing.T) {
+	clock := &waitingClock{fakeClock: newFakeClock(), chWaiting: make(chan struct{})}
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Second,
+		Burst:    1,
+		Clock:    clock,
+	})
+	limiter.Allow()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chErr := make(chan error)
+	go func() {
+		chErr <- limiter.Wait(ctx)
+	}()
+	<-clock.chWaiting
+
+	// Later reservation has already taken the waiter's token into account.
+	if delay, _ := limiter.Reserve(1); delay != 2*time.Second {
+		t.Errorf("Unexpected delay: %s", delay)
+	}
+
+	cancel()
+	if err := <-chErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+
+	clock.Advance(2 * time.Second)
+	if limiter.Allow() {
+		t.Error("Event was allowed along with the reserved one")
+	}
+}
+
+// waitingClock is a fakeClock which never fires, notifying when somebody starts waiting.
+type waitingClock struct {
+	*fakeClock
+	chWaiting chan struct{}
+}
+
+func (c *waitingClock) After(d time.Duration) <-chan time.Time {
+	c.chWaiting <- struct{}{}
+	return nil
+}
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	clock := newFakeClock()
+	limiter := async.NewSlidingWindow(async.SlidingWindowConfig{
+		Limit:  2,
+		Window: 10 * time.Second,
+		Clock:  clock,
+	})
+
+	expected := []bool{true, true, false}
+	var allowed []bool
+	for i := 0; i < 3; i++ {
+		allowed = append(allowed, limiter.Allow())
+	}
+	if !reflect.DeepEqual(allowed, expected) {
+		t.Errorf("Unexpected events allowed: %v", allowed)
+	}
+
+	// Previous window is still fully weighted.
+	clock.Advance(10 * time.Second)
+	if limiter.Allow() {
+		t.Error("Event was allowed while previous window is full")
+	}
+
+	// Half of the previous window has slid out.
+	clock.Advance(5 * time.Second)
+	if !limiter.Allow() {
+		t.Error("Event was not allowed after window has slid")
+	}
+	if limiter.Allow() {
+		t.Error("Event was allowed above the limit")
+	}
+}
+
+func TestSlidingWindow_Reserve(t *testing.T) {
+	limiter := async.NewSlidingWindow(async.SlidingWindowConfig{
+		Limit:  2,
+		Window: 10 * time.Second,
+		Clock:  newFakeClock(),
+	})
+
+	if delay, err := limiter.Reserve(2); delay != 0 || err != nil {
+		t.Errorf("Unexpected reservation: %s, %#v", delay, err)
+	}
+	if delay, err := limiter.Reserve(1); delay != 15*time.Second || err != nil {
+		t.Errorf("Unexpected reservation: %s, %#v", delay, err)
+	}
+	if _, err := limiter.Reserve(3); err != async.ErrReservationExceedsLimit {
+		t.Errorf("Unexpected reservation error: %#v", err)
+	}
+	if limiter.Allow() {
+		t.Error("Event was allowed before reserved ones")
+	}
+}
+
+func TestSlidingWindow_Wait(t *testing.T) {
+	clock := newFakeClock()
+	limiter := async.NewSlidingWindow(async.SlidingWindowConfig{
+		Limit:  1,
+		Window: time.Second,
+		Clock:  clock,
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.TODO()); err != nil {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+	}
+
+	// Previous window's counter is weighted as if its events were spread evenly,
+	// so the next event is allowed only once the previous window has slid out completely.
+	expected := []time.Duration{2 * time.Second, 2 * time.Second}
+	if delays := clock.Delays(); !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Unexpected delays: %v", delays)
+	}
+}
+
+func TestThrottle_returnsLimiterError(t *testing.T) {
+	limiter := async.NewTokenBucket(async.TokenBucketConfig{
+		Interval: time.Hour,
+		Burst:    1,
+	})
+	limiter.Allow()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	_, err := async.Throttle(ctx, limiter, func() (string, error) {
+		t.Error("Function was called despite limiter error")
+		return "", nil
+	})()
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+}
+
+func TestNewTokenBucket_panicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidRateLimiterConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewTokenBucket(async.TokenBucketConfig{})
+}
+
+func TestNewSlidingWindow_panicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidRateLimiterConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewSlidingWindow(async.SlidingWindowConfig{})
+}
@@ -0,0 +1,123 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ResultGroup groups functions returning the same type for asynchronous execution.
+// Method Execute() will block until all functions have executed and will return results in the order functions were added.
+type ResultGroup[T any] struct {
+	group    Group
+	promises []Promise[T]
+
+	// Rate limiter, see SetRateLimit().
+	limiter RateLimiter
+}
+
+// IndexedError is an error returned by a function of ResultGroup.
+// Index is the position of the function in the order functions were added to the group.
+type IndexedError struct {
+	Index int
+	Err   error
+}
+
+// Add registers function f with the execution group.
+func (g *ResultGroup[T]) Add(f func() (T, error)) {
+	index := len(g.promises)
+	task := taskName(f)
+
+	promise := AddToExecutionGroup(&g.group, func() (res T, err error) {
+		// Make sure both errors and panics record function's index.
+		// Panic is raised again by group's Execute() if panic policy requires so.
+		policy := g.group.effectivePanicPolicy()
+		defer func() {
+			if panicArg := recover(); panicArg != nil {
+				err = policy.handle(panicArg, task)
+			}
+			if err != nil {
+				err = IndexedError{Index: index, Err: err}
+			}
+		}()
+
+		// Make sure rate limit is respected, limiter's error records function's index as well.
+		if err := waitRateLimit(context.Background(), g.limiter); err != nil {
+			return res, err
+		}
+
+		return f()
+	})
+
+	g.promises = append(g.promises, promise)
+}
+
+// SetLimit limits the number of functions executing at the same time to n.
+// See Group.SetLimit() for additional information.
+func (g *ResultGroup[T]) SetLimit(n int) {
+	g.group.SetLimit(n)
+}
+
+// SetPanicPolicy sets panic policy for functions of this group, overriding package level policy.
+// See Group.SetPanicPolicy() for additional information.
+func (g *ResultGroup[T]) SetPanicPolicy(policy PanicPolicy) {
+	g.group.SetPanicPolicy(policy)
+}
+
+// SetRateLimit limits the rate at which functions are launched.
+// See Group.SetRateLimit() for additional information.
+func (g *ResultGroup[T]) SetRateLimit(limiter RateLimiter) {
+	g.limiter = limiter
+}
+
+// Execute functions added to the group.
+// Will block until all functions have executed and will return results in the order functions were added.
+// Aggregated error consists of IndexedError values sorted by index.
+// Result of failed function is whatever it returned along with the error.
+// If panic policy mode is PanicOnAwait, Execute panics with the first encountered PanicError.
+//
+// Execute can be called only once.
+// Calling Execute() repeatedly will result in an error.
+func (g *ResultGroup[T]) Execute() ([]T, AggregatedError) {
+	if errs := g.group.Execute(); errs != nil {
+		// Compare with the sentinel directly, since functions could return it wrapped in IndexedError.
+		if len(errs) == 1 && errs[0] == ErrGroupAlreadyExecuted {
+			return nil, errs
+		}
+
+		sort.SliceStable(errs, func(i, j int) bool {
+			return g.errorIndex(errs[i]) < g.errorIndex(errs[j])
+		})
+
+		return g.results(), errs
+	}
+
+	return g.results(), nil
+}
+
+// errorIndex returns index of the function which failed with the error.
+// Errors without index are placed after all the indexed ones.
+func (g *ResultGroup[T]) errorIndex(err error) int {
+	if indexedErr, ok := err.(IndexedError); ok {
+		return indexedErr.Index
+	}
+	return len(g.promises)
+}
+
+// results collects results from all promises.
+func (g *ResultGroup[T]) results() []T {
+	res := make([]T, len(g.promises))
+	for i, promise := range g.promises {
+		res[i] = promise()
+	}
+	return res
+}
+
+func (e IndexedError) Error() string {
+	return fmt.Sprintf("function #%d failed: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap returns the original error.
+func (e IndexedError) Unwrap() error {
+	return e.Err
+}
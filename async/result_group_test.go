@@ -0,0 +1,195 @@
+package async_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ error = async.IndexedError{}
+)
+
+func ExampleResultGroup() {
+	var group async.ResultGroup[int]
+
+	for i := 1; i <= 3; i++ {
+		i := i
+		group.Add(func() (int, error) {
+			// Perform some lengthy operation.
+
+			if i == 2 {
+				return 0, errors.New("lengthy operation failed")
+			}
+			return i * 10, nil
+		})
+	}
+
+	res, err := group.Execute()
+	for _, err := range err {
+		fmt.Println("Error:", err)
+	}
+	fmt.Println("Results:", res)
+
+	// Output:
+	// Error: function #1 failed: lengthy operation failed
+	// Results: [10 0 30]
+}
+
+func TestResultGroup_passesResultsInOrder(t *testing.T) {
+	var group async.ResultGroup[string]
+
+	group.Add(func() (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "dummy result 1", nil
+	})
+
+	group.Add(func() (string, error) {
+		return "dummy result 2", nil
+	})
+
+	res, err := group.Execute()
+	if err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+	if !reflect.DeepEqual(res, []string{"dummy result 1", "dummy result 2"}) {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+}
+
+func TestResultGroup_aggregatesIndexedErrors(t *testing.T) {
+	var group async.ResultGroup[string]
+
+	group.Add(func() (string, error) {
+		return "dummy result 1", nil
+	})
+
+	group.Add(func() (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "dummy result 2", dummyError2
+	})
+
+	group.Add(func() (string, error) {
+		panic(dummyError3)
+	})
+
+	res, err := group.Execute()
+	if !reflect.DeepEqual(res, []string{"dummy result 1", "dummy result 2", ""}) {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+
+	if len(err) != 2 {
+		t.Fatalf("Expected aggregated error to have 2 errors, but got: %#v", err)
+	}
+
+	var indexedError async.IndexedError
+	if !errors.As(err[0], &indexedError) || indexedError.Index != 1 || !errors.Is(indexedError, dummyError2) {
+		t.Errorf("Unexpected 1st error received from the execution group: %#v", err[0])
+	}
+	if !errors.As(err[1], &indexedError) || indexedError.Index != 2 || !errors.Is(indexedError, dummyError3) {
+		t.Errorf("Unexpected 2nd error received from the execution group: %#v", err[1])
+	}
+}
+
+func TestResultGroup_SetLimit(t *testing.T) {
+	var group async.ResultGroup[int]
+	group.SetLimit(1)
+
+	var running int
+	for i := 0; i < 3; i++ {
+		i := i
+		group.Add(func() (int, error) {
+			running++
+			defer func() { running-- }()
+
+			if running > 1 {
+				return 0, errors.New("functions are running concurrently")
+			}
+			return i, nil
+		})
+	}
+
+	res, err := group.Execute()
+	if err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+	if !reflect.DeepEqual(res, []int{0, 1, 2}) {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+}
+
+func TestResultGroup_callingExecuteRepeatedlyReturnsError(t *testing.T) {
+	var group async.ResultGroup[int]
+
+	group.Add(func() (int, error) {
+		return 1, nil
+	})
+
+	if _, err := group.Execute(); err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+
+	res, err := group.Execute()
+	if res != nil {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+	if !err.Has(async.ErrGroupAlreadyExecuted) {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+}
+
+func TestResultGroup_functionReturningGroupAlreadyExecuted(t *testing.T) {
+	var group async.ResultGroup[int]
+
+	group.Add(func() (int, error) {
+		return 1, nil
+	})
+	group.Add(func() (int, error) {
+		// Error of a nested group is not an error of this group.
+		return 2, async.ErrGroupAlreadyExecuted
+	})
+
+	res, err := group.Execute()
+	if !reflect.DeepEqual(res, []int{1, 2}) {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+
+	var indexedErr async.IndexedError
+	if len(err) != 1 || !errors.As(err[0], &indexedErr) || indexedErr.Index != 1 {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+}
+
+func TestResultGroup_SetRateLimit(t *testing.T) {
+	clock := newFakeClock()
+
+	var group async.ResultGroup[int]
+	group.SetRateLimit(async.NewSlidingWindow(async.SlidingWindowConfig{
+		Limit:  2,
+		Window: time.Second,
+		Clock:  clock,
+	}))
+
+	for i := 0; i < 3; i++ {
+		i := i
+		group.Add(func() (int, error) {
+			return i, nil
+		})
+	}
+
+	res, err := group.Execute()
+	if err != nil {
+		t.Errorf("Unexpected error received from the execution group: %#v", err)
+	}
+	if !reflect.DeepEqual(res, []int{0, 1, 2}) {
+		t.Errorf("Unexpected results received from the execution group: %#v", res)
+	}
+	if delays := clock.Delays(); len(delays) != 1 {
+		t.Errorf("Unexpected delays of rate limiter: %v", delays)
+	}
+}
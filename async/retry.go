@@ -0,0 +1,119 @@
+package async
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Default number of attempts, if neither MaxAttempts nor MaxElapsed limits retries.
+const defaultRetryMaxAttempts = 10
+
+// RetryPolicy defines how Retry() repeats function.
+type RetryPolicy struct {
+	// Backoff computes delays between attempts, attempts are repeated immediately if nil.
+	Backoff Backoff
+
+	// MaxAttempts limits the number of attempts including the first one, zero means no limit.
+	// If MaxElapsed is not set either, attempts are limited to 10.
+	MaxAttempts int
+
+	// MaxElapsed limits total time spent, including delays, zero means no limit.
+	// Retry gives up if the next delay would exceed this limit.
+	MaxElapsed time.Duration
+
+	// Retryable reports whether error should be retried, all errors are retried if nil.
+	// Errors wrapped with Permanent() are never retried.
+	Retryable func(error) bool
+
+	// Clock to measure time and wait between attempts, SystemClock is used if nil.
+	Clock Clock
+}
+
+// PermanentError is an error which Retry() does not retry.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps err to signal Retry() to give up immediately.
+// Returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return PermanentError{Err: err}
+}
+
+// Retry executes function f asynchronously, repeating it according to the policy until it succeeds.
+// Returns promise which will return result of the first successful attempt.
+//
+// When Retry gives up, promise returns AggregatedError with errors of all attempts.
+// If context is done while waiting for the next attempt, context's error is added to aggregated error as well.
+// Errors wrapped with Permanent() are added to aggregated error unwrapped.
+func Retry[T any](ctx context.Context, policy RetryPolicy, f func(context.Context) (T, error)) PromiseWithError[T] {
+	return Execute(func() (T, error) {
+		return retry(ctx, policy, f)
+	})
+}
+
+// retry function f according to the policy, see Retry().
+func retry[T any](ctx context.Context, policy RetryPolicy, f func(context.Context) (T, error)) (T, error) {
+	clock := clockOrDefault(policy.Clock)
+	start := clock.Now()
+
+	if policy.MaxAttempts <= 0 && policy.MaxElapsed <= 0 {
+		policy.MaxAttempts = defaultRetryMaxAttempts
+	}
+
+	var errs AggregatedError
+	var delay time.Duration
+
+	for attempt := 1; ; attempt++ {
+		res, err := f(ctx)
+		if err == nil {
+			return res, nil
+		}
+
+		var permanentErr PermanentError
+		if errors.As(err, &permanentErr) {
+			errs = append(errs, permanentErr.Err)
+			return res, errs
+		}
+
+		errs = append(errs, err)
+
+		if policy.Retryable != nil && !policy.Retryable(err) {
+			return res, errs
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return res, errs
+		}
+
+		if policy.Backoff != nil {
+			delay = policy.Backoff.Delay(attempt, delay)
+		}
+		if policy.MaxElapsed > 0 && clock.Now().Add(delay).Sub(start) > policy.MaxElapsed {
+			return res, errs
+		}
+
+		// Check context first, select does not prioritize between ready cases.
+		if ctx.Err() != nil {
+			return res, append(errs, ctx.Err())
+		}
+
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+			return res, append(errs, ctx.Err())
+		}
+	}
+}
+
+func (e PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e PermanentError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,206 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+// Ensure interface implementation
+var (
+	_ error = async.PermanentError{}
+)
+
+func ExampleRetry() {
+	attempts := 0
+
+	promise := async.Retry(context.TODO(), async.RetryPolicy{
+		Backoff:     async.ExponentialBackoff{Initial: time.Millisecond, Jitter: async.FullJitter},
+		MaxAttempts: 5,
+	}, func(ctx context.Context) (string, error) {
+		// Perform some unreliable operation.
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("temporary failure")
+		}
+
+		return "string result of unreliable operation", nil
+	})
+
+	res, err := promise()
+	fmt.Println("Result:", res)
+	fmt.Println("Error:", err)
+	fmt.Println("Attempts:", attempts)
+
+	// Output:
+	// Result: string result of unreliable operation
+	// Error: <nil>
+	// Attempts: 3
+}
+
+// failingFunc returns function which fails with errors from errs one by one and succeeds afterwards.
+func failingFunc(errs ...error) func(context.Context) (string, error) {
+	attempt := 0
+	return func(ctx context.Context) (string, error) {
+		attempt++
+		if attempt <= len(errs) {
+			return "", errs[attempt-1]
+		}
+		return "dummy result", nil
+	}
+}
+
+func TestRetry_succeedsAfterFailures(t *testing.T) {
+	clock := newFakeClock()
+
+	res, err := async.Retry(context.TODO(), async.RetryPolicy{
+		Backoff: async.ExponentialBackoff{Initial: time.Second},
+		Clock:   clock,
+	}, failingFunc(dummyError1, dummyError2, dummyError3))()
+
+	if res != "dummy result" {
+		t.Errorf("Unexpected result received from the promise: %#v", res)
+	}
+	if err != nil {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if delays := clock.Delays(); !reflect.DeepEqual(delays, expected) {
+		t.Errorf("Unexpected delays between attempts: %v", delays)
+	}
+}
+
+func TestRetry_MaxAttempts(t *testing.T) {
+	clock := newFakeClock()
+
+	_, err := async.Retry(context.TODO(), async.RetryPolicy{
+		Backoff:     async.ConstantBackoff(time.Second),
+		MaxAttempts: 2,
+		Clock:       clock,
+	}, failingFunc(dummyError1, dummyError2, dummyError3))()
+
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !reflect.DeepEqual(aggregatedError, async.AggregatedError{dummyError1, dummyError2}) {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+
+	if delays := clock.Delays(); len(delays) != 1 {
+		t.Errorf("Unexpected delays between attempts: %v", delays)
+	}
+}
+
+func TestRetry_zeroPolicyLimitsAttempts(t *testing.T) {
+	attempts := 0
+	_, err := async.Retry(context.TODO(), async.RetryPolicy{}, func(ctx context.Context) (string, error) {
+		attempts++
+		return "", dummyError
+	})()
+
+	if errs, _ := err.(async.AggregatedError); len(errs) != 10 {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+	if attempts != 10 {
+		t.Errorf("Unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestRetry_MaxElapsed(t *testing.T) {
+	clock := newFakeClock()
+
+	_, err := async.Retry(context.TODO(), async.RetryPolicy{
+		Backoff:    async.ExponentialBackoff{Initial: time.Second},
+		MaxElapsed: 5 * time.Second,
+		Clock:      clock,
+	}, failingFunc(dummyError1, dummyError2, dummyError3))()
+
+	// Attempts at 0s and 1s, next attempt at 3s, the following one at 7s would exceed the limit.
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !reflect.DeepEqual(aggregatedError, async.AggregatedError{dummyError1, dummyError2, dummyError3}) {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+}
+
+func TestRetry_Retryable(t *testing.T) {
+	_, err := async.Retry(context.TODO(), async.RetryPolicy{
+		Retryable: func(err error) bool {
+			return !errors.Is(err, dummyError2)
+		},
+		Clock: newFakeClock(),
+	}, failingFunc(dummyError1, dummyError2, dummyError3))()
+
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !reflect.DeepEqual(aggregatedError, async.AggregatedError{dummyError1, dummyError2}) {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+}
+
+func TestRetry_Permanent(t *testing.T) {
+	_, err := async.Retry(context.TODO(), async.RetryPolicy{
+		Clock: newFakeClock(),
+	}, failingFunc(dummyError1, async.Permanent(dummyError2), dummyError3))()
+
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !reflect.DeepEqual(aggregatedError, async.AggregatedError{dummyError1, dummyError2}) {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+
+	if async.Permanent(nil) != nil {
+		t.Error("Expected nil error not to be wrapped")
+	}
+}
+
+func TestRetry_stopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	attempts := 0
+	_, err := async.Retry(ctx, async.RetryPolicy{
+		Backoff: async.ConstantBackoff(time.Hour),
+	}, func(ctx context.Context) (string, error) {
+		attempts++
+		cancel()
+		return "", dummyError
+	})()
+
+	var aggregatedError async.AggregatedError
+	if !errors.As(err, &aggregatedError) {
+		t.Fatalf("Unexpected error received from the promise: %#v", err)
+	}
+	if !reflect.DeepEqual(aggregatedError, async.AggregatedError{dummyError, context.Canceled}) {
+		t.Errorf("Unexpected aggregated error received from the promise: %#v", aggregatedError)
+	}
+	if attempts != 1 {
+		t.Errorf("Unexpected number of attempts: %d", attempts)
+	}
+}
+
+func TestRetry_contextCancellationInterruptsDelay(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := async.Retry(ctx, async.RetryPolicy{
+		Backoff: async.ConstantBackoff(time.Hour),
+	}, failingFunc(dummyError))()
+
+	if !async.AggregateErrors(err).Has(context.DeadlineExceeded) {
+		t.Errorf("Unexpected error received from the promise: %#v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package async
+
+import (
+	"container/list"
+	"context"
+	"sync"
+)
+
+// Semaphore limits the total weight of concurrent operations, e.g. memory they use.
+// Waiters acquire semaphore in FIFO order, so a heavy waiter is not starved by light ones arriving after it.
+type Semaphore struct {
+	size int
+
+	mu      sync.Mutex
+	used    int
+	waiters list.List
+}
+
+type semaphoreWaiter struct {
+	weight int
+
+	// Channel is closed when weight is acquired on behalf of the waiter.
+	ready chan struct{}
+
+	// Function is called while holding the lock when weight is acquired on behalf of the waiter, may be nil.
+	acquired func()
+}
+
+// NewSemaphore creates weighted semaphore with the total size.
+// Panics with ErrInvalidSemaphoreSize if size is less than 1.
+func NewSemaphore(size int) *Semaphore {
+	if size < 1 {
+		panic(ErrInvalidSemaphoreSize)
+	}
+	return &Semaphore{size: size}
+}
+
+// Acquire semaphore with the weight, blocking until enough of semaphore is released or context is done.
+// Returns context's error if context is done first, nothing is acquired in this case.
+// Returns ErrSemaphoreWeightExceedsSize immediately if weight exceeds semaphore size.
+// Returns ErrInvalidSemaphoreWeight immediately if weight is less than 1.
+func (s *Semaphore) Acquire(ctx context.Context, weight int) error {
+	if weight < 1 {
+		return ErrInvalidSemaphoreWeight
+	}
+	if weight > s.size {
+		return ErrSemaphoreWeightExceedsSize
+	}
+
+	s.mu.Lock()
+	if s.fits(weight) && s.waiters.Len() == 0 {
+		s.used += weight
+		s.mu.Unlock()
+		return nil
+	}
+
+	// Check context first, no need to wait if it is already done.
+	if ctx.Err() != nil {
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+
+	waiter := &semaphoreWaiter{weight: weight, ready: make(chan struct{})}
+	elem := s.waiters.PushBack(waiter)
+	s.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if !s.dequeue(elem) {
+			s.used -= weight
+			s.notify()
+		}
+
+		return ctx.Err()
+	}
+}
+
+// TryAcquire semaphore with the weight without blocking.
+// Reports whether weight was acquired, it is not acquired if there are other waiters.
+// Weight which is less than 1 or exceeds semaphore size is never acquired.
+func (s *Semaphore) TryAcquire(weight int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if weight < 1 || weight > s.size || !s.fits(weight) || s.waiters.Len() > 0 {
+		return false
+	}
+
+	s.used += weight
+	return true
+}
+
+// acquireChan starts acquiring semaphore with the weight, function acquired is called once weight is acquired.
+// Returns channel which is closed once weight is acquired and function which stops waiting.
+// Stop function reports whether waiter was removed before weight was acquired, otherwise caller must release the weight.
+// Weight must be between 1 and semaphore size.
+func (s *Semaphore) acquireChan(weight int, acquired func()) (<-chan struct{}, func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	waiter := &semaphoreWaiter{weight: weight, ready: make(chan struct{}), acquired: acquired}
+	elem := s.waiters.PushBack(waiter)
+	s.notify()
+
+	return waiter.ready, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return s.dequeue(elem)
+	}
+}
+
+// Release semaphore with the weight acquired earlier.
+// Panics with ErrSemaphoreReleasedTooMuch if released weight exceeds acquired one.
+// Panics with ErrInvalidSemaphoreWeight if weight is less than 1.
+func (s *Semaphore) Release(weight int) {
+	if weight < 1 {
+		panic(ErrInvalidSemaphoreWeight)
+	}
+	if !s.release(weight) {
+		panic(ErrSemaphoreReleasedTooMuch)
+	}
+}
+
+// release semaphore with the weight.
+// Reports whether weight was released, nothing is released if weight exceeds acquired one.
+func (s *Semaphore) release(weight int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if weight > s.used {
+		return false
+	}
+
+	s.used -= weight
+	s.notify()
+
+	return true
+}
+
+// dequeue removes waiter from the queue, unless weight was already acquired on its behalf.
+// Reports whether waiter was removed.
+// Must be called while holding the lock.
+func (s *Semaphore) dequeue(elem *list.Element) bool {
+	waiter := elem.Value.(*semaphoreWaiter)
+
+	// Weight could have been acquired while waiting for the lock.
+	select {
+	case <-waiter.ready:
+		return false
+	default:
+	}
+
+	s.waiters.Remove(elem)
+
+	// Waiters behind this one could fit now.
+	s.notify()
+
+	return true
+}
+
+// fits reports whether weight can be acquired now.
+// Must be called while holding the lock.
+func (s *Semaphore) fits(weight int) bool {
+	return s.used+weight <= s.size
+}
+
+// notify waiters in FIFO order while their weight fits.
+// Stops at the first waiter which does not fit, so it is not starved by the following ones.
+// Must be called while holding the lock.
+func (s *Semaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		waiter := front.Value.(*semaphoreWaiter)
+		if !s.fits(waiter.weight) {
+			return
+		}
+
+		s.used += waiter.weight
+		s.waiters.Remove(front)
+		if waiter.acquired != nil {
+			waiter.acquired()
+		}
+		close(waiter.ready)
+	}
+}
+
+// Weighted wraps function f with semaphore.
+// Returned function acquires semaphore with the weight before calling f and releases it after f returns.
+// If acquiring fails, semaphore's error is returned without calling f.
+func Weighted[T any](ctx context.Context, s *Semaphore, weight int, f func() (T, error)) func() (T, error) {
+	return func() (res T, err error) {
+		if err := s.Acquire(ctx, weight); err != nil {
+			return res, err
+		}
+		defer s.Release(weight)
+
+		return f()
+	}
+}
@@ -0,0 +1,193 @@
+package async_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"dexm.lol/async"
+)
+
+func ExampleSemaphore() {
+	// Allow processing of up to 100 MB of files at the same time.
+	semaphore := async.NewSemaphore(100)
+
+	var group async.Group
+	group.SetSemaphore(semaphore)
+
+	for _, size := range []int{60, 30, 50} {
+		size := size
+		async.AddWeightedToExecutionGroup(&group, size, func() (interface{}, error) {
+			// Process file of the size, which requires the same amount of memory.
+			fmt.Printf("Processed file of %d MB\n", size)
+			return nil, nil
+		})
+	}
+
+	if err := group.Execute(); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// Unordered output:
+	// Processed file of 60 MB
+	// Processed file of 30 MB
+	// Processed file of 50 MB
+}
+
+// acquireAsync acquires semaphore in the background.
+// Returns channel which receives result of acquiring.
+func acquireAsync(ctx context.Context, semaphore *async.Semaphore, weight int) <-chan error {
+	ch := make(chan error, 1)
+	go func() { ch <- semaphore.Acquire(ctx, weight) }()
+
+	// Give goroutine time to start waiting.
+	time.Sleep(time.Millisecond)
+	return ch
+}
+
+func TestSemaphore_TryAcquire(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+
+	if !semaphore.TryAcquire(6) {
+		t.Fatal("Semaphore was not acquired")
+	}
+	if semaphore.TryAcquire(6) {
+		t.Fatal("Semaphore was acquired above its size")
+	}
+
+	semaphore.Release(6)
+	if !semaphore.TryAcquire(10) {
+		t.Error("Semaphore was not acquired after release")
+	}
+}
+
+func TestSemaphore_AcquireIsFIFO(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+	semaphore.TryAcquire(5)
+
+	// Light waiter does not overtake the heavy one, even though it fits.
+	heavy := acquireAsync(context.TODO(), semaphore, 10)
+	light := acquireAsync(context.TODO(), semaphore, 2)
+
+	if semaphore.TryAcquire(1) {
+		t.Error("Semaphore was acquired while there are waiters")
+	}
+
+	select {
+	case <-heavy:
+		t.Fatal("Heavy waiter acquired semaphore before it was released")
+	case <-light:
+		t.Fatal("Light waiter acquired semaphore before the heavy one")
+	default:
+	}
+
+	semaphore.Release(5)
+	if err := <-heavy; err != nil {
+		t.Fatalf("Unexpected error of the heavy waiter: %#v", err)
+	}
+
+	select {
+	case <-light:
+		t.Fatal("Light waiter acquired semaphore, while the heavy one holds it")
+	default:
+	}
+
+	semaphore.Release(10)
+	if err := <-light; err != nil {
+		t.Errorf("Unexpected error of the light waiter: %#v", err)
+	}
+}
+
+func TestSemaphore_AcquireRespectsContext(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+	semaphore.TryAcquire(5)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	heavy := acquireAsync(ctx, semaphore, 10)
+	light := acquireAsync(context.TODO(), semaphore, 5)
+
+	// Cancelled waiter is removed from the queue, letting the following waiters through.
+	cancel()
+	if err := <-heavy; !errors.Is(err, context.Canceled) {
+		t.Errorf("Unexpected error of the cancelled waiter: %#v", err)
+	}
+	if err := <-light; err != nil {
+		t.Errorf("Unexpected error of the light waiter: %#v", err)
+	}
+}
+
+func TestSemaphore_AcquireExceedingSize(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+
+	if err := semaphore.Acquire(context.TODO(), 11); err != async.ErrSemaphoreWeightExceedsSize {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+}
+
+func TestSemaphore_invalidWeight(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+
+	if err := semaphore.Acquire(context.TODO(), -5); err != async.ErrInvalidSemaphoreWeight {
+		t.Errorf("Unexpected error: %#v", err)
+	}
+	if semaphore.TryAcquire(0) {
+		t.Error("Semaphore was acquired with zero weight")
+	}
+
+	// Semaphore size is not affected.
+	if semaphore.TryAcquire(11) {
+		t.Error("Semaphore was acquired above its size")
+	}
+}
+
+func TestSemaphore_ReleasePanicsOnInvalidWeight(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidSemaphoreWeight {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	semaphore := async.NewSemaphore(10)
+	semaphore.TryAcquire(1)
+	semaphore.Release(-1)
+}
+
+func TestSemaphore_ReleasePanicsWhenReleasingTooMuch(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrSemaphoreReleasedTooMuch {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewSemaphore(10).Release(1)
+}
+
+func TestNewSemaphore_panicsOnInvalidSize(t *testing.T) {
+	defer func() {
+		if err := recover(); err != async.ErrInvalidSemaphoreSize {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	async.NewSemaphore(0)
+}
+
+func TestWeighted(t *testing.T) {
+	semaphore := async.NewSemaphore(10)
+
+	res, err := async.Weighted(context.TODO(), semaphore, 10, func() (string, error) {
+		if semaphore.TryAcquire(1) {
+			t.Error("Semaphore was not acquired by the function")
+		}
+		return "dummy result", nil
+	})()
+
+	if res != "dummy result" || err != nil {
+		t.Errorf("Unexpected result: %#v, %#v", res, err)
+	}
+	if !semaphore.TryAcquire(10) {
+		t.Error("Semaphore was not released after the function")
+	}
+}
@@ -0,0 +1,248 @@
+package channel
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// ConcurrencyLimiter adjusts the number of messages processed at the same time based on observed results.
+type ConcurrencyLimiter interface {
+	// Limit returns current concurrency limit.
+	Limit() int
+
+	// Observe result of processing a message, which took latency and failed with err (nil if succeeded).
+	Observe(latency time.Duration, err error)
+}
+
+// AIMDConfig configures AIMD concurrency limiter.
+type AIMDConfig struct {
+	// Min and Max bound concurrency limit, 0 < Min <= Max is required.
+	Min, Max int
+
+	// Initial concurrency limit, Min by default.
+	Initial int
+
+	// LatencyThreshold is the processing latency treated as overload, zero means that only errors are treated as overload.
+	LatencyThreshold time.Duration
+
+	// Increase of the limit after Limit() messages have succeeded, 1 by default.
+	Increase int
+
+	// Decrease is the factor limit is multiplied by on overload, must be between 0 and 1, 0.5 by default.
+	Decrease float64
+}
+
+// AIMDLimiter increases concurrency limit additively while messages succeed
+// and decreases it multiplicatively on errors or high latency.
+type AIMDLimiter struct {
+	config AIMDConfig
+
+	mu    sync.Mutex
+	limit float64
+}
+
+// GradientConfig configures gradient concurrency limiter.
+type GradientConfig struct {
+	// Min and Max bound concurrency limit, 0 < Min <= Max is required.
+	Min, Max int
+
+	// Initial concurrency limit, Min by default.
+	Initial int
+
+	// Tolerance is the ratio of latency to its long-term average, which is still not treated as overload, 1.5 by default.
+	Tolerance float64
+
+	// Smoothing is the weight of a new limit estimation, must be between 0 and 1, 0.2 by default.
+	Smoothing float64
+
+	// Window is the number of observations long-term average latency is computed over, 100 by default.
+	Window int
+}
+
+// GradientLimiter adjusts concurrency limit by the gradient of latency:
+// ratio of long-term average latency to the latest one.
+// Limit grows while latency is stable and shrinks proportionally when latency increases or messages fail.
+type GradientLimiter struct {
+	config GradientConfig
+
+	mu      sync.Mutex
+	limit   float64
+	average float64
+}
+
+// Ensure interface implementation
+var (
+	_ ConcurrencyLimiter = &AIMDLimiter{}
+	_ ConcurrencyLimiter = &GradientLimiter{}
+)
+
+// WithAdaptiveConcurrency makes the number of messages processed at the same time follow the limiter's limit.
+// Concurrency argument becomes the maximum number of workers, so it should not be less than limiter's maximum.
+// Each processed message is reported to the limiter with its latency and error.
+func WithAdaptiveConcurrency(limiter ConcurrencyLimiter) Option {
+	return func(o *options) {
+		o.gate = &concurrencyGate{limiter: limiter, changed: make(chan struct{})}
+	}
+}
+
+// NewAIMDLimiter creates AIMD concurrency limiter.
+// Panics with ErrInvalidLimiterConfig if config.Min or config.Max is invalid.
+func NewAIMDLimiter(config AIMDConfig) *AIMDLimiter {
+	if config.Min < 1 || config.Max < config.Min || config.Decrease < 0 || config.Decrease >= 1 {
+		panic(ErrInvalidLimiterConfig)
+	}
+	if config.Increase <= 0 {
+		config.Increase = 1
+	}
+	if config.Decrease == 0 {
+		config.Decrease = 0.5
+	}
+
+	return &AIMDLimiter{
+		config: config,
+		limit:  float64(clamp(config.Initial, config.Min, config.Max)),
+	}
+}
+
+// Limit returns current concurrency limit.
+func (l *AIMDLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// Observe result of processing a message.
+func (l *AIMDLimiter) Observe(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err != nil || l.config.LatencyThreshold > 0 && latency > l.config.LatencyThreshold {
+		l.limit *= l.config.Decrease
+	} else {
+		// Limit increases by Increase once per Limit() observations.
+		l.limit += float64(l.config.Increase) / math.Floor(l.limit)
+	}
+
+	l.limit = math.Max(float64(l.config.Min), math.Min(l.limit, float64(l.config.Max)))
+}
+
+// NewGradientLimiter creates gradient concurrency limiter.
+// Panics with ErrInvalidLimiterConfig if config.Min or config.Max is invalid.
+func NewGradientLimiter(config GradientConfig) *GradientLimiter {
+	if config.Min < 1 || config.Max < config.Min || config.Smoothing < 0 || config.Smoothing > 1 {
+		panic(ErrInvalidLimiterConfig)
+	}
+	if config.Tolerance <= 0 {
+		config.Tolerance = 1.5
+	}
+	if config.Smoothing == 0 {
+		config.Smoothing = 0.2
+	}
+	if config.Window <= 0 {
+		config.Window = 100
+	}
+
+	return &GradientLimiter{
+		config: config,
+		limit:  float64(clamp(config.Initial, config.Min, config.Max)),
+	}
+}
+
+// Limit returns current concurrency limit.
+func (l *GradientLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
+
+// Observe result of processing a message.
+func (l *GradientLimiter) Observe(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Errors are treated as overload, but their latency is not representative.
+	gradient, queue := 0.5, 0.0
+	if err == nil {
+		sample := math.Max(float64(latency), 1)
+		if l.average == 0 {
+			l.average = sample
+		} else {
+			l.average += (sample - l.average) / float64(l.config.Window)
+		}
+
+		gradient = math.Max(0.5, math.Min(1, l.config.Tolerance*l.average/sample))
+
+		// Square root of the limit allows some queueing, so the limit keeps growing while latency is stable.
+		queue = math.Sqrt(l.limit)
+	}
+
+	estimation := l.limit*gradient + queue
+	l.limit += (estimation - l.limit) * l.config.Smoothing
+
+	l.limit = math.Max(float64(l.config.Min), math.Min(l.limit, float64(l.config.Max)))
+}
+
+// concurrencyGate limits the number of messages processed at the same time by limiter's limit.
+type concurrencyGate struct {
+	limiter ConcurrencyLimiter
+
+	mu       sync.Mutex
+	inFlight int
+
+	// Channel is closed and replaced when a message completes, to wake up waiting workers.
+	changed chan struct{}
+}
+
+// acquire waits until the number of messages processed is below the limit or context is done.
+// Returns function, which must be called with the error of processing once the message is processed.
+func (g *concurrencyGate) acquire(ctx context.Context) (func(error), error) {
+	for {
+		// Check context first, select does not prioritize between ready cases.
+		// Checked on every iteration, so that a worker woken up after cancellation does not take the slot.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		g.mu.Lock()
+		if g.inFlight < g.limiter.Limit() {
+			g.inFlight++
+			g.mu.Unlock()
+			break
+		}
+		changed := g.changed
+		g.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	start := time.Now()
+	return func(err error) {
+		g.limiter.Observe(time.Since(start), err)
+
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		g.inFlight--
+		close(g.changed)
+		g.changed = make(chan struct{})
+	}, nil
+}
+
+// clamp value between min and max, value less than min is replaced by min.
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dexm.lol/channel"
+)
+
+// fixedLimiter has constant limit and counts observations.
+type fixedLimiter struct {
+	limit        int
+	observations int32
+}
+
+// Ensure interface implementation
+var (
+	_ channel.ConcurrencyLimiter = &fixedLimiter{}
+)
+
+func (l *fixedLimiter) Limit() int {
+	return l.limit
+}
+
+func (l *fixedLimiter) Observe(latency time.Duration, err error) {
+	atomic.AddInt32(&l.observations, 1)
+}
+
+func ExampleWithAdaptiveConcurrency() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 4)
+	for i := 1; i <= 4; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	limiter := channel.NewAIMDLimiter(channel.AIMDConfig{
+		Min:              1,
+		Max:              8,
+		LatencyThreshold: time.Second,
+	})
+
+	// Up to 8 workers are launched, but only limiter.Limit() of them process messages at the same time.
+	chErr := channel.Consume(ctx, 8, chIn, func(ctx context.Context, in int) error {
+		// Call backend, which may get overloaded.
+		return nil
+	}, channel.WithAdaptiveConcurrency(limiter))
+
+	for range chErr {
+	}
+
+	fmt.Println("Concurrency limit:", limiter.Limit())
+
+	// Output:
+	// Concurrency limit: 3
+}
+
+func TestProcessWithAdaptiveConcurrency(t *testing.T) {
+	const messages = 8
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, messages)
+	for i := 0; i < messages; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	limiter := &fixedLimiter{limit: 2}
+
+	var mu sync.Mutex
+	var running, maxRunning int
+
+	chRes, chErr := channel.Process(ctx, messages, chIn, func(ctx context.Context, in int) (int, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+
+		return in, nil
+	}, channel.WithAdaptiveConcurrency(limiter))
+
+	done := drain(chErr)
+	for range chRes {
+	}
+	<-done
+
+	if maxRunning != limiter.limit {
+		t.Errorf("Unexpected number of messages processed at the same time: %d", maxRunning)
+	}
+	if observations := atomic.LoadInt32(&limiter.observations); observations != messages {
+		t.Errorf("Unexpected number of observations: %d", observations)
+	}
+}
+
+func TestConsumeWithAdaptiveConcurrencyStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 2)
+	chIn <- 1
+	chIn <- 2
+
+	chStarted := make(chan struct{})
+	chErr := channel.Consume(ctx, 2, chIn, func(ctx context.Context, in int) error {
+		close(chStarted)
+		<-ctx.Done()
+		return nil
+	}, channel.WithAdaptiveConcurrency(&fixedLimiter{limit: 1}))
+
+	// Second message waits for the first one, until context is cancelled.
+	<-chStarted
+	cancel()
+
+	for err := range chErr {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Unexpected error received: %#v", err)
+		}
+	}
+}
+
+func TestAIMDLimiter(t *testing.T) {
+	limiter := channel.NewAIMDLimiter(channel.AIMDConfig{
+		Min:              2,
+		Max:              4,
+		LatencyThreshold: time.Second,
+	})
+
+	steps := []struct {
+		latency time.Duration
+		err     error
+		limit   int
+	}{
+		{latency: time.Millisecond, limit: 2},
+		{latency: time.Millisecond, limit: 3},
+		{latency: time.Millisecond, limit: 3},
+		{latency: time.Millisecond, limit: 3},
+		{latency: time.Millisecond, limit: 4},
+		{latency: time.Millisecond, limit: 4},
+		{latency: 2 * time.Second, limit: 2},
+		{latency: time.Millisecond, limit: 2},
+		{latency: time.Millisecond, limit: 3},
+		{err: errors.New("overloaded"), limit: 2},
+	}
+
+	for i, step := range steps {
+		limiter.Observe(step.latency, step.err)
+		if limit := limiter.Limit(); limit != step.limit {
+			t.Errorf("Unexpected limit after step %d: %d", i, limit)
+		}
+	}
+}
+
+func TestGradientLimiter(t *testing.T) {
+	limiter := channel.NewGradientLimiter(channel.GradientConfig{
+		Min:     1,
+		Max:     20,
+		Initial: 4,
+	})
+
+	// Limit grows while latency is stable.
+	for i := 0; i < 10; i++ {
+		limiter.Observe(10*time.Millisecond, nil)
+	}
+	grown := limiter.Limit()
+	if grown <= 4 {
+		t.Fatalf("Limit did not grow with stable latency: %d", grown)
+	}
+
+	// Limit shrinks when latency increases.
+	for i := 0; i < 5; i++ {
+		limiter.Observe(100*time.Millisecond, nil)
+	}
+	shrunk := limiter.Limit()
+	if shrunk >= grown {
+		t.Fatalf("Limit did not shrink with increased latency: %d", shrunk)
+	}
+
+	// Limit shrinks on errors down to the minimum.
+	for i := 0; i < 50; i++ {
+		limiter.Observe(0, errors.New("overloaded"))
+	}
+	if limit := limiter.Limit(); limit != 1 {
+		t.Errorf("Limit did not shrink to the minimum on errors: %d", limit)
+	}
+
+	// Limit does not exceed the maximum.
+	for i := 0; i < 200; i++ {
+		limiter.Observe(10*time.Millisecond, nil)
+	}
+	if limit := limiter.Limit(); limit != 20 {
+		t.Errorf("Limit did not grow to the maximum: %d", limit)
+	}
+}
+
+func TestNewAIMDLimiterPanicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidLimiterConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.NewAIMDLimiter(channel.AIMDConfig{Min: 2, Max: 1})
+}
+
+func TestNewGradientLimiterPanicsOnInvalidConfig(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidLimiterConfig {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.NewGradientLimiter(channel.GradientConfig{})
+}
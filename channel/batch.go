@@ -0,0 +1,109 @@
+package channel
+
+import (
+	"context"
+	"time"
+)
+
+// Maximum time to wait for the reader to receive partial batch after context is cancelled.
+const batchFlushTimeout = 50 * time.Millisecond
+
+// Batch groups messages of the channel into batches of up to size messages.
+// Size must be greater than 0, passing size less than 1 will result in panic.
+// You must close input channel or cancel context for output channel to be closed.
+//
+// Batch is emitted once it has size messages or once interval elapses since its first message, whichever occurs first.
+// Zero or negative interval disables time based emission.
+// When input channel is closed, partial batch is emitted before closing output channel.
+// Empty batches are never emitted.
+//
+// Emission of a batch is interrupted when context is cancelled, so reader may stop reading once context is cancelled.
+// Partial batch is still emitted after cancellation if reader receives it within a short timeout, otherwise it is discarded.
+func Batch[T any](ctx context.Context, channel <-chan T, size int, interval time.Duration) <-chan []T {
+	if size < 1 {
+		panic(ErrInvalidBatchSize)
+	}
+
+	chRes := make(chan []T)
+
+	go func() {
+		defer close(chRes)
+
+		var batch []T
+
+		// Timer of the current batch, nil when there is no batch.
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+		// Emit the batch, reports whether it was received before context was cancelled.
+		flush := func() bool {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+
+			if len(batch) == 0 {
+				return true
+			}
+
+			select {
+			case chRes <- batch:
+				batch = nil
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Make sure partial batch is emitted when input channel is closed or context is cancelled.
+		defer func() {
+			if flush() {
+				return
+			}
+
+			// Reader might have stopped reading on cancellation, so partial batch is not awaited indefinitely.
+			t := time.NewTimer(batchFlushTimeout)
+			defer t.Stop()
+
+			select {
+			case chRes <- batch:
+			case <-t.C:
+			}
+		}()
+
+		for {
+			// Check context first, select does not prioritize between ready cases.
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case message, ok := <-channel:
+				if !ok {
+					return
+				}
+
+				if batch == nil {
+					batch = make([]T, 0, size)
+					if interval > 0 {
+						timer = time.NewTimer(interval)
+						timeout = timer.C
+					}
+				}
+
+				batch = append(batch, message)
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-timeout:
+				if !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chRes
+}
@@ -0,0 +1,132 @@
+package channel_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"dexm.lol/channel"
+)
+
+func ExampleBatch() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 5)
+	for i := 1; i <= 5; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	for batch := range channel.Batch(ctx, chIn, 2, time.Second) {
+		fmt.Println("Batch received:", batch)
+	}
+
+	// Output:
+	// Batch received: [1 2]
+	// Batch received: [3 4]
+	// Batch received: [5]
+}
+
+func TestBatchEmitsPartialBatchAfterInterval(t *testing.T) {
+	const interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int)
+	defer close(chIn)
+
+	chRes := channel.Batch(ctx, chIn, 10, interval)
+
+	start := time.Now()
+	chIn <- 1
+	chIn <- 2
+
+	if diff := cmp.Diff([]int{1, 2}, <-chRes); diff != "" {
+		t.Error(diff)
+	}
+	if elapsed := time.Since(start); elapsed < interval {
+		t.Errorf("Partial batch was emitted before interval has elapsed: %s", elapsed)
+	}
+
+	// Interval is measured from the first message of the next batch.
+	chIn <- 3
+	if diff := cmp.Diff([]int{3}, <-chRes); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBatchFlushesPartialBatchOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// Input channel is never closed.
+	chIn := make(chan int)
+
+	chRes := channel.Batch(ctx, chIn, 10, 0)
+	chIn <- 1
+	chIn <- 2
+	cancel()
+
+	var batches [][]int
+	for batch := range chRes {
+		batches = append(batches, batch)
+	}
+
+	if diff := cmp.Diff([][]int{{1, 2}}, batches); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBatchStopsEmittingOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// Input channel is never closed.
+	chIn := make(chan int, 3)
+	chIn <- 1
+	chIn <- 2
+	chIn <- 3
+
+	chRes := channel.Batch(ctx, chIn, 1, 0)
+	if diff := cmp.Diff([]int{1}, <-chRes); diff != "" {
+		t.Error(diff)
+	}
+
+	// Reader stops reading on cancellation, pending batch is discarded after timeout.
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+
+	for batch := range chRes {
+		t.Errorf("Unexpected batch received: %v", batch)
+	}
+}
+
+func TestBatchDoesNotEmitEmptyBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int)
+	chRes := channel.Batch(ctx, chIn, 10, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+	close(chIn)
+
+	for batch := range chRes {
+		t.Errorf("Unexpected batch received: %v", batch)
+	}
+}
+
+func TestBatchPanicsOnInvalidSize(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidBatchSize {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.Batch(context.TODO(), make(chan int), 0, time.Second)
+}
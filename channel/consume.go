@@ -2,28 +2,82 @@ package channel
 
 import (
 	"context"
+	"sync"
 )
 
 // Consume channel concurrently.
 // Concurrency must be greater than 0, but it makes no sense to have it less than 2.
-// You must close input channel for error channel to be closed.
+// Passing concurrency less than 1 will result in panic.
+// You must close input channel or cancel context for error channel to be closed.
+//
+// Exactly concurrency workers are reading input channel.
+// When context is cancelled, workers stop reading new messages and drop errors nobody has received yet.
+// Error channel is closed only after all the in-flight calls of function f have returned.
+// Processing can be configured with options, e.g. WithRateLimit() or WithAdaptiveConcurrency().
 func Consume[T any](
 	ctx context.Context,
 	concurrency int,
 	channel <-chan T,
 	f func(context.Context, T) error,
+	opts ...Option,
 ) <-chan error {
+	if concurrency < 1 {
+		panic(ErrInvalidConcurrency)
+	}
+
+	o := newOptions(opts)
+
 	chErr := make(chan error)
 
-	go func(chErr chan<- error) {
-		defer close(chErr)
+	// Wait group to track when all the workers are complete.
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+
+	// Asynchronous function which will close error channel when all the workers have finished.
+	go func() {
+		wg.Wait()
+		close(chErr)
+	}()
+
+	// Launch all workers.
+	for i := 0; i < concurrency; i++ {
+		go func(chErr chan<- error) {
+			defer wg.Done()
+
+			for {
+				// Check context first, select does not prioritize between ready cases.
+				if ctx.Err() != nil {
+					return
+				}
+
+				var message T
+				var ok bool
+
+				select {
+				case message, ok = <-channel:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+
+				release, err := o.acquire(ctx)
+				if err == nil {
+					err = f(ctx, message)
+					release(err)
+				}
 
-		for message := range channel {
-			if err := f(ctx, message); err != nil {
-				chErr <- err
+				if err != nil {
+					select {
+					case chErr <- err:
+					case <-ctx.Done():
+						return
+					}
+				}
 			}
-		}
-	}(chErr)
+		}(chErr)
+	}
 
 	return chErr
 }
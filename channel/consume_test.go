@@ -3,6 +3,10 @@ package channel_test
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
 
 	"dexm.lol/channel"
 )
@@ -38,3 +42,132 @@ func ExampleConsume() {
 	// Consumed message: message 1
 	// Error received: error consuming message: message 2
 }
+
+func TestConsumeRunsConcurrently(t *testing.T) {
+	const concurrency = 4
+	const delay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, concurrency)
+	for i := 0; i < concurrency; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	start := time.Now()
+
+	chErr := channel.Consume(ctx, concurrency, chIn, func(ctx context.Context, in int) error {
+		time.Sleep(delay)
+		return nil
+	})
+
+	for err := range chErr {
+		t.Errorf("Unexpected error received: %#v", err)
+	}
+
+	// Serial processing would take concurrency * delay.
+	if elapsed := time.Since(start); elapsed >= 2*delay {
+		t.Errorf("Consuming took too long, items were not consumed concurrently: %s", elapsed)
+	}
+}
+
+func TestConsumeStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// Input channel is never closed.
+	chIn := make(chan int)
+
+	var consumed int32
+	chErr := channel.Consume(ctx, 2, chIn, func(ctx context.Context, in int) error {
+		atomic.AddInt32(&consumed, 1)
+		return nil
+	})
+
+	chIn <- 1
+	cancel()
+
+	// Error channel must be closed even though input channel is still open.
+	for err := range chErr {
+		t.Errorf("Unexpected error received: %#v", err)
+	}
+
+	select {
+	case chIn <- 2:
+		t.Error("Message was consumed after context cancellation")
+	default:
+	}
+
+	if consumed := atomic.LoadInt32(&consumed); consumed != 1 {
+		t.Errorf("Unexpected number of consumed messages: %d", consumed)
+	}
+}
+
+func TestConsumeDoesNotBlockOnErrorsAfterContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 2)
+	chIn <- 1
+	chIn <- 2
+	close(chIn)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	// Nobody reads errors until all the messages have failed.
+	chErr := channel.Consume(ctx, 2, chIn, func(ctx context.Context, in int) error {
+		defer wg.Done()
+		return fmt.Errorf("error consuming message: %d", in)
+	})
+
+	wg.Wait()
+	cancel()
+
+	// Errors which were not received before cancellation may be dropped, but channel must be closed.
+	for range chErr {
+	}
+}
+
+func TestConsumeWaitsForInFlightCallsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 1)
+	chIn <- 1
+
+	chStarted := make(chan struct{})
+	var finished int32
+
+	chErr := channel.Consume(ctx, 1, chIn, func(ctx context.Context, in int) error {
+		close(chStarted)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		atomic.StoreInt32(&finished, 1)
+		return nil
+	})
+
+	<-chStarted
+	cancel()
+
+	for range chErr {
+	}
+
+	if atomic.LoadInt32(&finished) != 1 {
+		t.Error("Error channel was closed before in-flight call has returned")
+	}
+}
+
+func TestConsumePanicsOnInvalidConcurrency(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidConcurrency {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.Consume(context.TODO(), 0, make(chan int), func(ctx context.Context, in int) error {
+		return nil
+	})
+}
@@ -0,0 +1,12 @@
+package channel
+
+import (
+	"errors"
+)
+
+// Predefined errors.
+var (
+	ErrInvalidConcurrency   = errors.New("concurrency must be greater than 0")
+	ErrInvalidLimiterConfig = errors.New("concurrency limiter must have limits 0 < min <= max and valid factors")
+	ErrInvalidBatchSize     = errors.New("batch size must be greater than 0")
+)
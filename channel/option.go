@@ -0,0 +1,52 @@
+package channel
+
+import (
+	"context"
+)
+
+// Option configures processing of a channel.
+type Option func(*options)
+
+// Limiter limits the rate of processing messages.
+// Rate limiters of dexm.lol/async package implement this interface.
+type Limiter interface {
+	// Wait blocks until a message can be processed or context is done.
+	Wait(ctx context.Context) error
+}
+
+type options struct {
+	limiter Limiter
+	gate    *concurrencyGate
+}
+
+// WithRateLimit makes workers wait for the limiter before processing each message.
+// If waiting fails (e.g. context is cancelled), limiter's error is reported instead of processing the message.
+func WithRateLimit(limiter Limiter) Option {
+	return func(o *options) {
+		o.limiter = limiter
+	}
+}
+
+// newOptions applies options to the default configuration.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// acquire permission to process a message, waiting for the rate limiter and adaptive concurrency limit if they are set.
+// Returns function, which must be called with the error of processing once the message is processed.
+func (o options) acquire(ctx context.Context) (func(error), error) {
+	if o.limiter != nil {
+		if err := o.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.gate == nil {
+		return func(error) {}, nil
+	}
+	return o.gate.acquire(ctx)
+}
@@ -0,0 +1,219 @@
+package channel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"dexm.lol/channel"
+)
+
+// tickerLimiter allows one message per tick.
+type tickerLimiter struct {
+	ticker *time.Ticker
+}
+
+// countingLimiter counts calls of Wait and fails with err if it is set.
+type countingLimiter struct {
+	waits int32
+	err   error
+}
+
+// Ensure interface implementation
+var (
+	_ channel.Limiter = &tickerLimiter{}
+	_ channel.Limiter = &countingLimiter{}
+)
+
+func (l *tickerLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l.ticker.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.waits, 1)
+	return l.err
+}
+
+func ExampleWithRateLimit() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 3)
+	for i := 1; i <= 3; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	// Rate limiters of dexm.lol/async package can be used as well.
+	limiter := &tickerLimiter{ticker: time.NewTicker(time.Millisecond)}
+	defer limiter.ticker.Stop()
+
+	chErr := channel.Consume(ctx, 2, chIn, func(ctx context.Context, in int) error {
+		fmt.Println("Message processed:", in)
+		return nil
+	}, channel.WithRateLimit(limiter))
+
+	for range chErr {
+	}
+
+	// Unordered output:
+	// Message processed: 1
+	// Message processed: 2
+	// Message processed: 3
+}
+
+func TestProcessWithRateLimit(t *testing.T) {
+	const ticks = 5
+	const interval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, ticks)
+	for i := 0; i < ticks; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	limiter := &tickerLimiter{ticker: time.NewTicker(interval)}
+	defer limiter.ticker.Stop()
+
+	start := time.Now()
+
+	chRes, chErr := channel.Process(ctx, ticks, chIn, func(ctx context.Context, in int) (int, error) {
+		return in, nil
+	}, channel.WithRateLimit(limiter))
+
+	done := drain(chErr)
+	for range chRes {
+	}
+	<-done
+
+	if elapsed := time.Since(start); elapsed < ticks*interval {
+		t.Errorf("Rate limit was not respected, processing took %s", elapsed)
+	}
+}
+
+func TestProcessReportsRateLimitErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 2)
+	chIn <- 1
+	chIn <- 2
+	close(chIn)
+
+	limitErr := errors.New("rate limit exceeded")
+	limiter := &countingLimiter{err: limitErr}
+
+	chRes, chErr := channel.Process(ctx, 2, chIn, func(ctx context.Context, in int) (int, error) {
+		t.Error("Message was processed despite rate limiter error")
+		return in, nil
+	}, channel.WithRateLimit(limiter))
+
+	done := drain(chRes)
+
+	var errs []error
+	for err := range chErr {
+		errs = append(errs, err)
+	}
+	<-done
+
+	if diff := cmp.Diff([]error{limitErr, limitErr}, errs, cmpopts.EquateErrors()); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestConsumeWithRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 4)
+	for i := 0; i < 4; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	limiter := &countingLimiter{}
+
+	var processed int32
+	chErr := channel.Consume(ctx, 2, chIn, func(ctx context.Context, in int) error {
+		atomic.AddInt32(&processed, 1)
+		return nil
+	}, channel.WithRateLimit(limiter))
+
+	for range chErr {
+	}
+
+	if waits := atomic.LoadInt32(&limiter.waits); waits != 4 {
+		t.Errorf("Unexpected number of rate limiter waits: %d", waits)
+	}
+	if processed := atomic.LoadInt32(&processed); processed != 4 {
+		t.Errorf("Unexpected number of processed messages: %d", processed)
+	}
+}
+
+func TestProcessOrderedWithRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 4)
+	for i := 0; i < 4; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	limiter := &countingLimiter{}
+
+	chRes, chErr := channel.ProcessOrdered(ctx, 2, chIn, func(ctx context.Context, in int) (int, error) {
+		return in * 10, nil
+	}, channel.WithRateLimit(limiter))
+
+	done := drain(chErr)
+
+	var res []int
+	for r := range chRes {
+		res = append(res, r)
+	}
+	<-done
+
+	if diff := cmp.Diff([]int{0, 10, 20, 30}, res); diff != "" {
+		t.Error(diff)
+	}
+	if waits := atomic.LoadInt32(&limiter.waits); waits != 4 {
+		t.Errorf("Unexpected number of rate limiter waits: %d", waits)
+	}
+}
+
+func TestProcessResultsWithRateLimit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 1)
+	chIn <- 1
+	close(chIn)
+
+	limitErr := errors.New("rate limit exceeded")
+
+	chRes := channel.ProcessResults(ctx, 1, chIn, func(ctx context.Context, in int) (int, error) {
+		t.Error("Message was processed despite rate limiter error")
+		return in, nil
+	}, channel.WithRateLimit(&countingLimiter{err: limitErr}))
+
+	for res := range chRes {
+		if res.Input != 1 || res.Err != limitErr {
+			t.Errorf("Unexpected result: %#v", res)
+		}
+	}
+}
@@ -2,32 +2,66 @@ package channel
 
 import (
 	"context"
+	"sync"
 )
 
 // Process channel concurrently.
 // Concurrency must be greater than 0, but it makes no sense to have it less than 2.
+// Passing concurrency less than 1 will result in panic.
 // You must close input channel for output and error channels to be closed.
+//
+// Exactly concurrency workers are reading input channel.
+// Output and error channels are closed only after all the workers have finished.
+// Processing can be configured with options, e.g. WithRateLimit() or WithAdaptiveConcurrency().
 func Process[T, R any](
 	ctx context.Context,
 	concurrency int,
 	channel <-chan T,
 	f func(context.Context, T) (R, error),
+	opts ...Option,
 ) (<-chan R, <-chan error) {
+	if concurrency < 1 {
+		panic(ErrInvalidConcurrency)
+	}
+
+	o := newOptions(opts)
+
 	chRes := make(chan R)
 	chErr := make(chan error)
 
-	go func(chRes chan<- R, chErr chan<- error) {
-		defer close(chRes)
-		defer close(chErr)
+	// Wait group to track when all the workers are complete.
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+
+	// Asynchronous function which will close output channels when all the workers have finished.
+	go func() {
+		wg.Wait()
+		close(chRes)
+		close(chErr)
+	}()
+
+	// Launch all workers.
+	for i := 0; i < concurrency; i++ {
+		go func(chRes chan<- R, chErr chan<- error) {
+			defer wg.Done()
+
+			for message := range channel {
+				release, err := o.acquire(ctx)
+
+				var res R
+				if err == nil {
+					res, err = f(ctx, message)
+					release(err)
+				}
 
-		for message := range channel {
-			if res, err := f(ctx, message); err != nil {
-				chErr <- err
-			} else {
-				chRes <- res
+				if err != nil {
+					chErr <- err
+				} else {
+					chRes <- res
+				}
 			}
-		}
-	}(chRes, chErr)
+		}(chRes, chErr)
+	}
 
 	return chRes, chErr
 }
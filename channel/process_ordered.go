@@ -0,0 +1,85 @@
+package channel
+
+import (
+	"context"
+)
+
+type processOrderedMessageType[R any] struct {
+	res R
+	err error
+}
+
+// ProcessOrdered processes channel concurrently, but emits results in the same order as messages were received.
+// Concurrency must be greater than 0, but it makes no sense to have it less than 2.
+// Passing concurrency less than 1 will result in panic.
+// You must close input channel for output and error channels to be closed.
+//
+// Each message produces either a result or an error, these are emitted strictly in the input order.
+// At most concurrency messages are processed or waiting to be emitted at the same time.
+// That way slow message blocks reading of the input channel instead of buffering unlimited number of results.
+// Processing can be configured with options, e.g. WithRateLimit() or WithAdaptiveConcurrency(),
+// messages wait for limiters in the input order.
+func ProcessOrdered[T, R any](
+	ctx context.Context,
+	concurrency int,
+	channel <-chan T,
+	f func(context.Context, T) (R, error),
+	opts ...Option,
+) (<-chan R, <-chan error) {
+	if concurrency < 1 {
+		panic(ErrInvalidConcurrency)
+	}
+
+	o := newOptions(opts)
+
+	chRes := make(chan R)
+	chErr := make(chan error)
+
+	// Queue of per message channels in the input order.
+	// One message is always held by the emitter, so queue capacity is reduced by one to keep the window size equal to concurrency.
+	chQueue := make(chan chan processOrderedMessageType[R], concurrency-1)
+
+	// Asynchronous function which reads input channel and launches processing of each message.
+	go func() {
+		defer close(chQueue)
+
+		for message := range channel {
+			// This channel is buffered. It will be written to only once.
+			// That way processing goroutine ends even if emitter is not waiting for it yet.
+			chMsg := make(chan processOrderedMessageType[R], 1)
+
+			// Blocks until there is a free place in the window.
+			chQueue <- chMsg
+
+			// Wait for limiters before launching processing, so messages are launched in the input order.
+			release, err := o.acquire(ctx)
+			if err != nil {
+				chMsg <- processOrderedMessageType[R]{err: err}
+				continue
+			}
+
+			go func(message T) {
+				var msg processOrderedMessageType[R]
+				msg.res, msg.err = f(ctx, message)
+				release(msg.err)
+				chMsg <- msg
+			}(message)
+		}
+	}()
+
+	// Asynchronous function which emits results in the input order.
+	go func() {
+		defer close(chRes)
+		defer close(chErr)
+
+		for chMsg := range chQueue {
+			if msg := <-chMsg; msg.err != nil {
+				chErr <- msg.err
+			} else {
+				chRes <- msg.res
+			}
+		}
+	}()
+
+	return chRes, chErr
+}
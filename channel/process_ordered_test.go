@@ -0,0 +1,181 @@
+package channel_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"dexm.lol/channel"
+)
+
+func ExampleProcessOrdered() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan time.Duration, 3)
+	chIn <- 30 * time.Millisecond
+	chIn <- 20 * time.Millisecond
+	chIn <- 10 * time.Millisecond
+	close(chIn)
+
+	chRes, chErr := channel.ProcessOrdered(ctx, 3, chIn, func(ctx context.Context, in time.Duration) (string, error) {
+		time.Sleep(in)
+		return fmt.Sprintf("slept for %s", in), nil
+	})
+
+	for res := range chRes {
+		fmt.Println("Result received:", res)
+	}
+	for err := range chErr {
+		fmt.Println("Error received:", err.Error())
+	}
+
+	// Output:
+	// Result received: slept for 30ms
+	// Result received: slept for 20ms
+	// Result received: slept for 10ms
+}
+
+func TestProcessOrderedPreservesInputOrder(t *testing.T) {
+	const count = 8
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, count)
+	for i := 0; i < count; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	chRes, chErr := channel.ProcessOrdered(ctx, 4, chIn, func(ctx context.Context, in int) (int, error) {
+		// Later messages finish earlier.
+		time.Sleep(time.Duration(count-in) * time.Millisecond)
+		if in%3 == 0 {
+			return 0, fmt.Errorf("error processing message: %d", in)
+		}
+		return in, nil
+	})
+
+	// Results and errors are emitted in order, so both channels have to be read together.
+	var actual []string
+	for chRes != nil || chErr != nil {
+		select {
+		case res, ok := <-chRes:
+			if !ok {
+				chRes = nil
+				continue
+			}
+			actual = append(actual, fmt.Sprint(res))
+		case err, ok := <-chErr:
+			if !ok {
+				chErr = nil
+				continue
+			}
+			actual = append(actual, err.Error())
+		}
+	}
+
+	expected := []string{
+		"error processing message: 0",
+		"1",
+		"2",
+		"error processing message: 3",
+		"4",
+		"5",
+		"error processing message: 6",
+		"7",
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestProcessOrderedRunsConcurrently(t *testing.T) {
+	const concurrency = 4
+	const delay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, concurrency)
+	for i := 0; i < concurrency; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	start := time.Now()
+
+	chRes, _ := channel.ProcessOrdered(ctx, concurrency, chIn, func(ctx context.Context, in int) (int, error) {
+		time.Sleep(delay)
+		return in, nil
+	})
+
+	for range chRes {
+	}
+
+	// Serial processing would take concurrency * delay.
+	if elapsed := time.Since(start); elapsed >= 2*delay {
+		t.Errorf("Processing took too long, items were not processed concurrently: %s", elapsed)
+	}
+}
+
+func TestProcessOrderedLimitsReorderWindow(t *testing.T) {
+	const concurrency = 3
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	chRelease := make(chan struct{})
+	var started int32
+
+	chRes, _ := channel.ProcessOrdered(ctx, concurrency, chIn, func(ctx context.Context, in int) (int, error) {
+		atomic.AddInt32(&started, 1)
+		if in == 0 {
+			// Slow head message.
+			<-chRelease
+		}
+		return in, nil
+	})
+
+	// Give other messages a chance to be processed.
+	time.Sleep(10 * time.Millisecond)
+
+	if started := atomic.LoadInt32(&started); started != concurrency {
+		t.Errorf("Unexpected number of started messages while head message is blocked: %d", started)
+	}
+
+	close(chRelease)
+
+	var actual []int
+	for res := range chRes {
+		actual = append(actual, res)
+	}
+
+	expected := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestProcessOrderedPanicsOnInvalidConcurrency(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidConcurrency {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.ProcessOrdered(context.TODO(), 0, make(chan int), func(ctx context.Context, in int) (int, error) {
+		return in, nil
+	})
+}
@@ -3,6 +3,11 @@ package channel_test
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 
 	"dexm.lol/channel"
 )
@@ -38,3 +43,94 @@ func ExampleProcess() {
 	// Result received: processed message: message 1
 	// Error received: error processing message: message 2
 }
+
+func TestProcessRunsConcurrently(t *testing.T) {
+	const concurrency = 4
+	const delay = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, concurrency)
+	for i := 0; i < concurrency; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	start := time.Now()
+
+	chRes, chErr := channel.Process(ctx, concurrency, chIn, func(ctx context.Context, in int) (int, error) {
+		time.Sleep(delay)
+		return in, nil
+	})
+
+	actual := make(map[int]bool)
+	for res := range chRes {
+		actual[res] = true
+	}
+	for err := range chErr {
+		t.Errorf("Unexpected error received: %#v", err)
+	}
+
+	// Serial processing would take concurrency * delay.
+	if elapsed := time.Since(start); elapsed >= 2*delay {
+		t.Errorf("Processing took too long, items were not processed concurrently: %s", elapsed)
+	}
+
+	expected := map[int]bool{0: true, 1: true, 2: true, 3: true}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestProcessClosesChannelsAfterAllWorkersFinish(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 2)
+	chIn <- 1
+	chIn <- 2
+	close(chIn)
+
+	var processed int32
+
+	chRes, chErr := channel.Process(ctx, 2, chIn, func(ctx context.Context, in int) (int, error) {
+		defer atomic.AddInt32(&processed, 1)
+
+		if in == 2 {
+			// Make sure one worker finishes noticeably later than the other.
+			time.Sleep(10 * time.Millisecond)
+			return 0, fmt.Errorf("error processing message: %d", in)
+		}
+		return in, nil
+	})
+
+	if res := <-chRes; res != 1 {
+		t.Errorf("Unexpected result received: %#v", res)
+	}
+	if err := <-chErr; err == nil {
+		t.Error("Expected to receive an error")
+	}
+
+	if _, ok := <-chRes; ok {
+		t.Error("Expected output channel to be closed")
+	}
+	if _, ok := <-chErr; ok {
+		t.Error("Expected error channel to be closed")
+	}
+	if processed := atomic.LoadInt32(&processed); processed != 2 {
+		t.Errorf("Channels were closed before all workers finished, processed: %d", processed)
+	}
+}
+
+func TestProcessPanicsOnInvalidConcurrency(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidConcurrency {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.Process(context.TODO(), 0, make(chan int), func(ctx context.Context, in int) (int, error) {
+		return in, nil
+	})
+}
@@ -0,0 +1,93 @@
+package channel
+
+import (
+	"context"
+	"sync"
+)
+
+// Result of processing a single message.
+type Result[T, R any] struct {
+	// Input message which was processed.
+	Input T
+
+	// Value returned by processing function.
+	Value R
+
+	// Err returned by processing function.
+	Err error
+}
+
+// ProcessResults processes channel concurrently and emits results and errors into a single channel.
+// Concurrency must be greater than 0, but it makes no sense to have it less than 2.
+// Passing concurrency less than 1 will result in panic.
+// You must close input channel or cancel context for output channel to be closed.
+//
+// Each result carries input message together with returned value and error, so errors can be correlated with their messages.
+// Draining output channel is always sufficient for processing to progress.
+// When context is cancelled, workers stop reading new messages and drop results nobody has received yet.
+// Processing can be configured with options, e.g. WithRateLimit() or WithAdaptiveConcurrency().
+func ProcessResults[T, R any](
+	ctx context.Context,
+	concurrency int,
+	channel <-chan T,
+	f func(context.Context, T) (R, error),
+	opts ...Option,
+) <-chan Result[T, R] {
+	if concurrency < 1 {
+		panic(ErrInvalidConcurrency)
+	}
+
+	o := newOptions(opts)
+
+	chRes := make(chan Result[T, R])
+
+	// Wait group to track when all the workers are complete.
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+
+	// Asynchronous function which will close output channel when all the workers have finished.
+	go func() {
+		wg.Wait()
+		close(chRes)
+	}()
+
+	// Launch all workers.
+	for i := 0; i < concurrency; i++ {
+		go func(chRes chan<- Result[T, R]) {
+			defer wg.Done()
+
+			for {
+				// Check context first, select does not prioritize between ready cases.
+				if ctx.Err() != nil {
+					return
+				}
+
+				res := Result[T, R]{}
+				var ok bool
+
+				select {
+				case res.Input, ok = <-channel:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+
+				var release func(error)
+				if release, res.Err = o.acquire(ctx); res.Err == nil {
+					res.Value, res.Err = f(ctx, res.Input)
+					release(res.Err)
+				}
+
+				select {
+				case chRes <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(chRes)
+	}
+
+	return chRes
+}
@@ -0,0 +1,156 @@
+package channel_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"dexm.lol/channel"
+)
+
+func ExampleProcessResults() {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	type input struct {
+		message    string
+		shouldFail bool
+	}
+
+	chIn := make(chan input, 2)
+	chIn <- input{message: "message 1", shouldFail: false}
+	chIn <- input{message: "message 2", shouldFail: true}
+	close(chIn)
+
+	chRes := channel.ProcessResults(ctx, 2, chIn, func(ctx context.Context, in input) (string, error) {
+		if in.shouldFail {
+			return "", fmt.Errorf("error processing message: %s", in.message)
+		}
+		return fmt.Sprintf("processed message: %s", in.message), nil
+	})
+
+	for res := range chRes {
+		if res.Err != nil {
+			fmt.Printf("Error received for %q: %s\n", res.Input.message, res.Err)
+		} else {
+			fmt.Printf("Result received for %q: %s\n", res.Input.message, res.Value)
+		}
+	}
+
+	// Unordered output:
+	// Result received for "message 1": processed message: message 1
+	// Error received for "message 2": error processing message: message 2
+}
+
+func TestProcessResultsCorrelatesErrorsWithInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 4)
+	for i := 0; i < 4; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	chRes := channel.ProcessResults(ctx, 2, chIn, func(ctx context.Context, in int) (int, error) {
+		if in%2 == 1 {
+			return 0, fmt.Errorf("odd message")
+		}
+		return in * 10, nil
+	})
+
+	var failed, succeeded []int
+	for res := range chRes {
+		if res.Err != nil {
+			failed = append(failed, res.Input)
+		} else {
+			if res.Value != res.Input*10 {
+				t.Errorf("Unexpected result received for message %d: %d", res.Input, res.Value)
+			}
+			succeeded = append(succeeded, res.Input)
+		}
+	}
+
+	sort.Ints(failed)
+	sort.Ints(succeeded)
+
+	if diff := cmp.Diff([]int{1, 3}, failed); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff([]int{0, 2}, succeeded); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestProcessResultsDoesNotBlockOnErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	chIn := make(chan int, 4)
+	for i := 0; i < 4; i++ {
+		chIn <- i
+	}
+	close(chIn)
+
+	// Only errors are produced, with separate error channel this would have blocked without reading it.
+	chRes := channel.ProcessResults(ctx, 1, chIn, func(ctx context.Context, in int) (int, error) {
+		return 0, fmt.Errorf("error processing message: %d", in)
+	})
+
+	count := 0
+	for range chRes {
+		count++
+	}
+
+	if count != 4 {
+		t.Errorf("Unexpected number of results received: %d", count)
+	}
+}
+
+func TestProcessResultsStopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	// Input channel is never closed.
+	chIn := make(chan int)
+
+	chRes := channel.ProcessResults(ctx, 2, chIn, func(ctx context.Context, in int) (int, error) {
+		return in, nil
+	})
+
+	chIn <- 1
+	cancel()
+
+	// Output channel must be closed even though input channel is still open and result was never received.
+	select {
+	case <-drain(chRes):
+	case <-time.After(100 * time.Millisecond):
+		t.Error("Output channel was not closed after context cancellation")
+	}
+}
+
+func TestProcessResultsPanicsOnInvalidConcurrency(t *testing.T) {
+	defer func() {
+		if err := recover(); err != channel.ErrInvalidConcurrency {
+			t.Errorf("Unexpected panic: %#v", err)
+		}
+	}()
+
+	channel.ProcessResults(context.TODO(), 0, make(chan int), func(ctx context.Context, in int) (int, error) {
+		return in, nil
+	})
+}
+
+func drain[T any](ch <-chan T) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range ch {
+		}
+	}()
+	return done
+}